@@ -0,0 +1,18 @@
+\connect api;
+
+-- User Session -------------------------------------------------------------------------------------------------------------------------------------
+-- Each row is a refresh token family: token_id holds the jti of the only refresh token that may still be used.
+-- DROP TABLE public.usr_session;
+CREATE TABLE if not exists public.usr_session (
+    id uuid DEFAULT uuid_generate_v4() NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    auth_id bigint NOT NULL,
+    token_id uuid NOT NULL,
+    expires_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    CONSTRAINT pkey_usr_session PRIMARY KEY (id),
+    CONSTRAINT fk_usr_session_auth FOREIGN KEY (auth_id) REFERENCES public.usr_auth (id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists idx_usr_session_auth_id ON public.usr_session USING btree (auth_id);
@@ -0,0 +1,8 @@
+\connect api;
+
+-- User Session client ------------------------------------------------------------------------------------------------------------------------------
+ALTER TABLE public.usr_session ADD COLUMN if not exists ip varchar(45) NULL;
+
+ALTER TABLE public.usr_session ADD COLUMN if not exists user_agent varchar(255) NULL;
+
+ALTER TABLE public.usr_session ADD COLUMN if not exists last_used_at timestamptz NULL;
@@ -0,0 +1,21 @@
+\connect api;
+
+-- User Password Reset ------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_password_reset_id;
+CREATE SEQUENCE if not exists public.seq_usr_password_reset_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_password_reset;
+CREATE TABLE if not exists public.usr_password_reset (
+    id bigint DEFAULT nextval('seq_usr_password_reset_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    auth_id bigint NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz NULL,
+    CONSTRAINT pkey_usr_password_reset PRIMARY KEY (id),
+    CONSTRAINT uni_usr_password_reset_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_usr_password_reset_auth FOREIGN KEY (auth_id) REFERENCES public.usr_auth (id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists idx_usr_password_reset_auth_id ON public.usr_password_reset USING btree (auth_id);
@@ -0,0 +1,10 @@
+\connect api;
+
+-- User Auth two-factor -----------------------------------------------------------------------------------------------------------------------------
+ALTER TABLE public.usr_auth ADD COLUMN if not exists totp_secret varchar(64) NULL;
+
+ALTER TABLE public.usr_auth ADD COLUMN if not exists totp_enabled bool DEFAULT false NOT NULL;
+
+ALTER TABLE public.usr_auth ADD COLUMN if not exists totp_last_step bigint DEFAULT 0 NOT NULL;
+
+ALTER TABLE public.usr_auth ADD COLUMN if not exists totp_recovery_codes text [ ] DEFAULT '{}' NOT NULL;
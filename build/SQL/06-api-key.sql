@@ -0,0 +1,25 @@
+\connect api;
+
+-- User API Key -------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_api_key_id;
+CREATE SEQUENCE if not exists public.seq_usr_api_key_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_api_key;
+CREATE TABLE if not exists public.usr_api_key (
+    id bigint DEFAULT nextval('seq_usr_api_key_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    auth_id bigint NOT NULL,
+    name varchar(100) NOT NULL,
+    prefix varchar(16) NOT NULL,
+    key_hash varchar(64) NOT NULL,
+    permissions text[] DEFAULT '{}' NOT NULL,
+    expires_at timestamptz NULL,
+    revoked_at timestamptz NULL,
+    last_used_at timestamptz NULL,
+    CONSTRAINT pkey_usr_api_key PRIMARY KEY (id),
+    CONSTRAINT uni_usr_api_key_prefix UNIQUE (prefix),
+    CONSTRAINT fk_usr_api_key_auth FOREIGN KEY (auth_id) REFERENCES public.usr_auth (id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists idx_usr_api_key_auth_id ON public.usr_api_key USING btree (auth_id);
@@ -0,0 +1,6 @@
+\connect api;
+
+-- User Auth lockout --------------------------------------------------------------------------------------------------------------------------------
+ALTER TABLE public.usr_auth ADD COLUMN if not exists failed_logins integer DEFAULT 0 NOT NULL;
+
+ALTER TABLE public.usr_auth ADD COLUMN if not exists locked_until timestamptz NULL;
@@ -0,0 +1,4 @@
+\connect api;
+
+-- User Auth password history -----------------------------------------------------------------------------------------------------------------------
+ALTER TABLE public.usr_auth ADD COLUMN if not exists password_history text [ ] DEFAULT '{}' NOT NULL;
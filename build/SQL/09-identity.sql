@@ -0,0 +1,22 @@
+\connect api;
+
+-- User Identity ------------------------------------------------------------------------------------------------------------------------------------
+-- DROP SEQUENCE IF EXISTS public.seq_usr_identity_id;
+CREATE SEQUENCE if not exists public.seq_usr_identity_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_identity;
+CREATE TABLE if not exists public.usr_identity (
+    id bigint DEFAULT nextval('seq_usr_identity_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    auth_id bigint NOT NULL,
+    issuer varchar(255) NOT NULL,
+    subject varchar(255) NOT NULL,
+    mail varchar(255) NOT NULL,
+    last_login_at timestamptz NULL,
+    CONSTRAINT pkey_usr_identity PRIMARY KEY (id),
+    CONSTRAINT uni_usr_identity_subject UNIQUE (issuer, subject),
+    CONSTRAINT fk_usr_identity_auth FOREIGN KEY (auth_id) REFERENCES public.usr_auth (id) ON DELETE CASCADE
+);
+
+CREATE INDEX if not exists idx_usr_identity_auth_id ON public.usr_identity USING btree (auth_id);
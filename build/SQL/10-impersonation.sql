@@ -0,0 +1,32 @@
+\connect api;
+
+-- User Session actor -------------------------------------------------------------------------------------------------------------------------------
+-- Sessions opened by impersonation keep the impersonating user, and can't be refreshed.
+ALTER TABLE public.usr_session ADD COLUMN if not exists actor_auth_id bigint NULL REFERENCES public.usr_auth (id) ON DELETE CASCADE;
+
+-- User Audit ---------------------------------------------------------------------------------------------------------------------------------------
+-- No foreign keys, so the trail outlives the users it mentions. Requests changing state under impersonation are
+-- recorded too, with their method, path and response status.
+-- DROP SEQUENCE IF EXISTS public.seq_usr_audit_id;
+CREATE SEQUENCE if not exists public.seq_usr_audit_id INCREMENT BY 1 MINVALUE 1 MAXVALUE 9223372036854775807 START 1 CACHE 1 NO CYCLE;
+
+-- DROP TABLE public.usr_audit;
+CREATE TABLE if not exists public.usr_audit (
+    id bigint DEFAULT nextval('seq_usr_audit_id':: regclass) NOT NULL,
+    created_at timestamptz DEFAULT NOW() NOT NULL,
+    updated_at timestamptz DEFAULT NOW() NOT NULL,
+    "action" varchar(50) NOT NULL,
+    actor_auth_id bigint NOT NULL,
+    auth_id bigint NULL,
+    session_id uuid NULL,
+    ip varchar(45) NULL,
+    user_agent varchar(255) NULL,
+    "method" varchar(10) NULL,
+    "path" varchar(255) NULL,
+    status smallint NULL,
+    CONSTRAINT pkey_usr_audit PRIMARY KEY (id)
+);
+
+CREATE INDEX if not exists idx_usr_audit_actor_auth_id ON public.usr_audit USING btree (actor_auth_id);
+
+CREATE INDEX if not exists idx_usr_audit_auth_id ON public.usr_audit USING btree (auth_id);
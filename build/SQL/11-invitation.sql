@@ -0,0 +1,5 @@
+\connect api;
+
+-- User email verification --------------------------------------------------------------------------------------------------------------------------
+-- Set when the user accepts its invitation or resets its password, both proving it receives mail at the address.
+ALTER TABLE public.usr_user ADD COLUMN if not exists mail_verified_at timestamptz NULL;
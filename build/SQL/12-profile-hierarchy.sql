@@ -0,0 +1,9 @@
+\connect api;
+
+-- User Profile hierarchy ---------------------------------------------------------------------------------------------------------------------------
+-- A profile inherits every permission of its parent, recursively. Parents can't be deleted while they have children.
+ALTER TABLE public.usr_profile ADD COLUMN if not exists parent_id bigint NULL;
+ALTER TABLE public.usr_profile DROP CONSTRAINT if exists fk_usr_profile_parent;
+ALTER TABLE public.usr_profile ADD CONSTRAINT fk_usr_profile_parent FOREIGN KEY (parent_id) REFERENCES public.usr_profile (id);
+ALTER TABLE public.usr_profile DROP CONSTRAINT if exists chk_usr_profile_parent;
+ALTER TABLE public.usr_profile ADD CONSTRAINT chk_usr_profile_parent CHECK (parent_id <> id);
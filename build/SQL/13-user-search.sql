@@ -0,0 +1,32 @@
+\connect api;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- User search --------------------------------------------------------------------------------------------------------------------------------------
+-- unaccent() is only stable, as its dictionary could change, so indexes are built on this immutable wrapper instead.
+CREATE OR REPLACE FUNCTION public.f_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+-- Text search configuration removing accents and lowering words, without stemming, as names aren't prose.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'simple_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION public.simple_unaccent (COPY = pg_catalog.simple);
+        ALTER TEXT SEARCH CONFIGURATION public.simple_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH public.unaccent, pg_catalog.simple;
+    END IF;
+END
+$$;
+
+-- Kept up to date by PostgreSQL on every insert and update; name and username weigh more than mail in the rank.
+ALTER TABLE public.usr_user ADD COLUMN if not exists search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('public.simple_unaccent', coalesce("name", '')), 'A') ||
+    setweight(to_tsvector('public.simple_unaccent', coalesce(username, '')), 'A') ||
+    setweight(to_tsvector('public.simple_unaccent', coalesce(mail, '')), 'B')
+) STORED;
+
+CREATE INDEX if not exists idx_usr_user_search_vector ON public.usr_user USING gin (search_vector);
+CREATE INDEX if not exists idx_usr_user_name_trgm ON public.usr_user USING gin (public.f_unaccent(LOWER("name")) gin_trgm_ops);
+CREATE INDEX if not exists idx_usr_user_username_trgm ON public.usr_user USING gin (public.f_unaccent(LOWER(username)) gin_trgm_ops);
+CREATE INDEX if not exists idx_usr_user_mail_trgm ON public.usr_user USING gin (public.f_unaccent(LOWER(mail)) gin_trgm_ops);
@@ -3,6 +3,7 @@ package main
 import (
 	_ "github.com/raulaguila/go-api/configs"
 	"github.com/raulaguila/go-api/internal/api/rest"
+	"github.com/raulaguila/go-api/internal/infra/mail"
 	"github.com/raulaguila/go-api/internal/infra/minio"
 	"github.com/raulaguila/go-api/internal/infra/pgsql"
 )
@@ -22,5 +23,5 @@ import (
 // @name							Authorization
 // @description 					Type "Bearer" followed by a space and the JWT token.
 func main() {
-	rest.New(pgsql.ConnectPostgresDB(), minio.ConnectMinio())
+	rest.New(pgsql.ConnectPostgresDB(), minio.ConnectMinio(), mail.NewMailSender())
 }
@@ -4,6 +4,9 @@ package main
 
 import (
 	"bufio"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -15,11 +18,15 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nexidian/gocliselect"
-	"golang.org/x/crypto/bcrypt"
 
+	"github.com/raulaguila/go-api/pkg/hasher"
 	"github.com/raulaguila/go-api/pkg/packhub"
 )
 
+func printPrivateKey(block *pem.Block) {
+	fmt.Printf("\nPrivate key: %v\n\n", base64.StdEncoding.EncodeToString(pem.EncodeToMemory(block)))
+}
+
 func generateRSAPrivateToken() {
 	fmt.Println()
 	menu := gocliselect.NewMenu("Bit size of the key")
@@ -35,18 +42,47 @@ func generateRSAPrivateToken() {
 	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
 	packhub.PanicIfErr(err)
 
-	fmt.Printf("\nPrivate key: %v\n\n", base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{
+	printPrivateKey(&pem.Block{
 		Type:  "RSA PRIVATE KEY",
 		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
-	})))
+	})
+}
+
+func generateECDSAPrivateToken() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	packhub.PanicIfErr(err)
+
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	packhub.PanicIfErr(err)
+
+	printPrivateKey(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func generateEd25519PrivateToken() {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	packhub.PanicIfErr(err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	packhub.PanicIfErr(err)
+
+	printPrivateKey(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
 }
 
 func hashPassword() {
+	fmt.Println()
+	menu := gocliselect.NewMenu("Hash algorithm")
+
+	menu.AddItem("Argon2id", hasher.AlgorithmArgon2id)
+	menu.AddItem("Bcrypt", hasher.AlgorithmBcrypt)
+
+	passwordHasher, err := hasher.New(menu.Display())
+	packhub.PanicIfErr(err)
+
 	fmt.Print("\nUser password: ")
 	scanner := bufio.NewScanner(os.Stdin)
 	scanner.Scan()
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(scanner.Text()), bcrypt.DefaultCost)
+	hash, err := passwordHasher.Hash(scanner.Text())
 	packhub.PanicIfErr(err)
 
 	fmt.Printf("Hash: %s\n\n", hash)
@@ -60,7 +96,9 @@ func main() {
 	printMenu := func() string {
 		menu := gocliselect.NewMenu("Chose an option")
 
-		menu.AddItem("Generate RSA Token", "rsa")
+		menu.AddItem("Generate RSA Token (RS256)", "rsa")
+		menu.AddItem("Generate ECDSA Token (ES256)", "ecdsa")
+		menu.AddItem("Generate Ed25519 Token (EdDSA)", "ed25519")
 		menu.AddItem("Hash user password", "hash")
 		menu.AddItem("New user token", "token")
 		menu.AddItem("Exit", "exit")
@@ -74,6 +112,10 @@ func main() {
 			return
 		case "rsa":
 			generateRSAPrivateToken()
+		case "ecdsa":
+			generateECDSAPrivateToken()
+		case "ed25519":
+			generateEd25519PrivateToken()
 		case "token":
 			generateUserToken()
 		case "hash":
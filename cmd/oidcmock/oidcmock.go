@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/raulaguila/go-api/pkg/oidc/oidctest"
+)
+
+// A local OpenID Connect provider to try the OIDC login against, e.g. with
+// OIDC_ISSUER='http://localhost:9096', OIDC_CLIENT_ID='go-api' and OIDC_CLIENT_SECRET='secret'.
+// It logs in without asking for credentials; pass login_hint to log in as another subject.
+func main() {
+	addr := flag.String("addr", "localhost:9096", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9096", "issuer URL, as reached by the API and the browser")
+	clientID := flag.String("client-id", "go-api", "client id")
+	clientSecret := flag.String("client-secret", "secret", "client secret")
+	subject := flag.String("subject", "mock-user", "subject of the id tokens")
+	claims := flag.String("claims", `{"email":"mock.user@email.com","email_verified":true,"name":"Mock User"}`, "JSON object of extra id token claims")
+	flag.Parse()
+
+	server, err := oidctest.New(*clientID, *clientSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := json.Unmarshal([]byte(*claims), &server.Claims); err != nil {
+		log.Fatal(err)
+	}
+
+	server.Issuer, server.Subject = *issuer, *subject
+	log.Printf("OpenID Connect mock provider listening on %s, issuer %s", *addr, *issuer)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
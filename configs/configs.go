@@ -1,18 +1,26 @@
 package configs
 
 import (
-	"crypto/rsa"
 	"embed"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/netip"
 	"os"
 	"path"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
 
+	"github.com/raulaguila/go-api/pkg/hasher"
+	"github.com/raulaguila/go-api/pkg/keyset"
+	"github.com/raulaguila/go-api/pkg/ldapauth"
+	"github.com/raulaguila/go-api/pkg/oidc"
 	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/passpolicy"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
@@ -20,11 +28,48 @@ var (
 	//go:embed locales/*
 	Locales embed.FS
 
-	AccessPrivateKey *rsa.PrivateKey
+	Production bool
+
+	DevUserID    uint
+	DevProfileID uint
+	DevNetworks  []netip.Prefix
+
+	AccessKeys       *keyset.KeySet
 	AccessExpiration time.Duration
 
-	RefreshPrivateKey *rsa.PrivateKey
+	RefreshKeys       *keyset.KeySet
 	RefreshExpiration time.Duration
+
+	TokenIssuer   string
+	TokenAudience string
+
+	AuthCookieDomain   string
+	AuthCookieSameSite string
+
+	PasswordResetExpiration time.Duration
+	InvitationExpiration    time.Duration
+	PasswordPolicy          *passpolicy.Policy
+	PasswordHasher          hasher.PasswordHasher
+
+	LoginMaxAttempts    int
+	LoginLockExpiration time.Duration
+	LoginIPMaxAttempts  int
+	LoginIPExpiration   time.Duration
+
+	ImpersonationExpiration time.Duration
+
+	OIDCProvider       *oidc.Provider
+	OIDCLinkByEmail    bool
+	OIDCAutoProvision  bool
+	OIDCDefaultProfile string
+	OIDCProfileRules   []oidc.Rule
+
+	LDAPClient         *ldapauth.Client
+	LDAPEmailTrusted   bool
+	LDAPLinkByEmail    bool
+	LDAPAutoProvision  bool
+	LDAPDefaultProfile string
+	LDAPProfileRules   []ldapauth.GroupRule
 )
 
 func init() {
@@ -38,24 +83,224 @@ func init() {
 	packhub.PanicIfErr(err)
 
 	{
-		accessDecodedKey, err := base64.StdEncoding.DecodeString(os.Getenv("ACCESS_TOKEN"))
+		AccessKeys, err = parseKeySet(os.Getenv("ACCESS_TOKEN"), os.Getenv("ACCESS_TOKEN_RETIRED"))
 		packhub.PanicIfErr(err)
 
-		AccessPrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM(accessDecodedKey)
+		AccessExpiration, err = utils.DurationFromString(os.Getenv("ACCESS_TOKEN_EXPIRE"), time.Minute)
 		packhub.PanicIfErr(err)
+	}
 
-		AccessExpiration, err = utils.DurationFromString(os.Getenv("ACCESS_TOKEN_EXPIRE"), time.Minute)
+	{
+		RefreshKeys, err = parseKeySet(os.Getenv("RFRESH_TOKEN"), os.Getenv("RFRESH_TOKEN_RETIRED"))
+		packhub.PanicIfErr(err)
+
+		RefreshExpiration, err = utils.DurationFromString(os.Getenv("RFRESH_TOKEN_EXPIRE"), time.Minute)
 		packhub.PanicIfErr(err)
 	}
 
+	Production = production(os.Getenv("API_ENVIRONMENT"))
+	if userID := os.Getenv("DEV_USER_ID"); userID != "" {
+		if Production {
+			packhub.PanicIfErr(errors.New("the development identity can't be enabled in production"))
+		}
+
+		DevUserID, DevProfileID, DevNetworks, err = parseDevIdentity(userID, os.Getenv("DEV_PROFILE_ID"), os.Getenv("DEV_NETWORKS"))
+		packhub.PanicIfErr(err)
+	}
+
+	TokenIssuer, TokenAudience = os.Getenv("TOKEN_ISSUER"), os.Getenv("TOKEN_AUDIENCE")
+	AuthCookieDomain, AuthCookieSameSite = os.Getenv("AUTH_COOKIE_DOMAIN"), os.Getenv("AUTH_COOKIE_SAMESITE")
+
+	PasswordResetExpiration, err = utils.DurationFromString(os.Getenv("PASSWORD_RESET_EXPIRE"), time.Minute)
+	packhub.PanicIfErr(err)
+
+	InvitationExpiration, err = utils.DurationFromString(os.Getenv("INVITATION_EXPIRE"), time.Hour)
+	packhub.PanicIfErr(err)
+
 	{
-		refreshDecodedKey, err := base64.StdEncoding.DecodeString(os.Getenv("RFRESH_TOKEN"))
+		PasswordPolicy = &passpolicy.Policy{
+			RequireUpper:  os.Getenv("PASSWORD_REQUIRE_UPPER") == "1",
+			RequireLower:  os.Getenv("PASSWORD_REQUIRE_LOWER") == "1",
+			RequireDigit:  os.Getenv("PASSWORD_REQUIRE_DIGIT") == "1",
+			RequireSymbol: os.Getenv("PASSWORD_REQUIRE_SYMBOL") == "1",
+		}
+
+		PasswordPolicy.MinLength, err = parseCount(os.Getenv("PASSWORD_MIN_LENGTH"))
 		packhub.PanicIfErr(err)
 
-		RefreshPrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM(refreshDecodedKey)
+		PasswordPolicy.History, err = parseCount(os.Getenv("PASSWORD_HISTORY"))
 		packhub.PanicIfErr(err)
+	}
 
-		RefreshExpiration, err = utils.DurationFromString(os.Getenv("RFRESH_TOKEN_EXPIRE"), time.Minute)
+	switch algorithm := os.Getenv("PASSWORD_HASHER"); algorithm {
+	case hasher.AlgorithmBcrypt:
+		cost, err := strconv.Atoi(os.Getenv("BCRYPT_COST"))
+		packhub.PanicIfErr(err)
+
+		PasswordHasher = hasher.NewBcrypt(cost)
+	case hasher.AlgorithmArgon2id:
+		memory, err := strconv.ParseUint(os.Getenv("ARGON2_MEMORY"), 10, 32)
+		packhub.PanicIfErr(err)
+
+		iterations, err := strconv.ParseUint(os.Getenv("ARGON2_ITERATIONS"), 10, 32)
+		packhub.PanicIfErr(err)
+
+		parallelism, err := strconv.ParseUint(os.Getenv("ARGON2_PARALLELISM"), 10, 8)
+		packhub.PanicIfErr(err)
+
+		PasswordHasher = hasher.NewArgon2id(uint32(memory), uint32(iterations), uint8(parallelism))
+	default:
+		packhub.PanicIfErr(fmt.Errorf("%w: %q", hasher.ErrUnknownAlgorithm, algorithm))
+	}
+
+	{
+		LoginMaxAttempts, err = strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS"))
+		packhub.PanicIfErr(err)
+
+		LoginLockExpiration, err = utils.DurationFromString(os.Getenv("LOGIN_LOCK_EXPIRE"), time.Minute)
+		packhub.PanicIfErr(err)
+
+		LoginIPMaxAttempts, err = strconv.Atoi(os.Getenv("LOGIN_IP_MAX_ATTEMPTS"))
+		packhub.PanicIfErr(err)
+
+		LoginIPExpiration, err = utils.DurationFromString(os.Getenv("LOGIN_IP_EXPIRE"), time.Minute)
 		packhub.PanicIfErr(err)
 	}
+
+	ImpersonationExpiration, err = utils.DurationFromString(os.Getenv("IMPERSONATION_EXPIRE"), time.Minute)
+	packhub.PanicIfErr(err)
+
+	if issuer := os.Getenv("OIDC_ISSUER"); issuer != "" {
+		OIDCProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       issuer,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+		})
+
+		OIDCLinkByEmail = os.Getenv("OIDC_LINK_BY_EMAIL") == "1"
+		OIDCAutoProvision = os.Getenv("OIDC_AUTO_PROVISION") == "1"
+		OIDCDefaultProfile = os.Getenv("OIDC_DEFAULT_PROFILE")
+
+		OIDCProfileRules, err = oidc.ParseRules(os.Getenv("OIDC_PROFILE_RULES"))
+		packhub.PanicIfErr(err)
+	}
+
+	if ldapURL := os.Getenv("LDAP_URL"); ldapURL != "" {
+		LDAPClient = ldapauth.New(ldapauth.Config{
+			URL:               ldapURL,
+			StartTLS:          os.Getenv("LDAP_START_TLS") == "1",
+			BindDN:            os.Getenv("LDAP_BIND_DN"),
+			BindPassword:      os.Getenv("LDAP_BIND_PASSWORD"),
+			BaseDN:            os.Getenv("LDAP_BASE_DN"),
+			UserFilter:        os.Getenv("LDAP_USER_FILTER"),
+			UsernameAttribute: os.Getenv("LDAP_USERNAME_ATTRIBUTE"),
+			EmailAttribute:    os.Getenv("LDAP_EMAIL_ATTRIBUTE"),
+			NameAttribute:     os.Getenv("LDAP_NAME_ATTRIBUTE"),
+			GroupAttribute:    os.Getenv("LDAP_GROUP_ATTRIBUTE"),
+		})
+
+		LDAPEmailTrusted = os.Getenv("LDAP_EMAIL_TRUSTED") == "1"
+		LDAPLinkByEmail = os.Getenv("LDAP_LINK_BY_EMAIL") == "1"
+		LDAPAutoProvision = os.Getenv("LDAP_AUTO_PROVISION") == "1"
+		LDAPDefaultProfile = os.Getenv("LDAP_DEFAULT_PROFILE")
+
+		LDAPProfileRules, err = ldapauth.ParseGroupRules(os.Getenv("LDAP_PROFILE_RULES"))
+		packhub.PanicIfErr(err)
+	}
+}
+
+// production reports whether the environment is to be treated as production: any but an explicit
+// development one, so a missing or misspelled API_ENVIRONMENT never allows the development identity.
+func production(environment string) bool {
+	return environment != "development"
+}
+
+// parseCount parses a count, which can't be negative.
+func parseCount(value string) (int, error) {
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if count < 0 {
+		return 0, fmt.Errorf("invalid count %d: can't be negative", count)
+	}
+
+	return count, nil
+}
+
+// parseDevIdentity parses the development identity's user and optional profile ids, and its comma
+// separated networks, given in CIDR notation.
+func parseDevIdentity(userID, profileID, networks string) (uint, uint, []netip.Prefix, error) {
+	parsedUserID, err := strconv.ParseUint(userID, 10, 0)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	var parsedProfileID uint64
+	if profileID != "" {
+		if parsedProfileID, err = strconv.ParseUint(profileID, 10, 0); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+
+	parsedNetworks := make([]netip.Prefix, 0)
+	for _, network := range strings.Split(networks, ",") {
+		if network = strings.TrimSpace(network); network == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		parsedNetworks = append(parsedNetworks, prefix.Masked())
+	}
+
+	return uint(parsedUserID), uint(parsedProfileID), parsedNetworks, nil
+}
+
+// parseKeySet builds a key set from the active key and a comma separated list of the retired ones.
+// Keys are base64 encoded PEMs, optionally prefixed by their algorithm, e.g. "ES256:<key>".
+func parseKeySet(active, retired string) (*keyset.KeySet, error) {
+	parseKey := func(encoded string) (*keyset.Key, error) {
+		algorithm, encoded, found := strings.Cut(strings.TrimSpace(encoded), ":")
+		if !found {
+			algorithm, encoded = "", algorithm
+		}
+
+		decodedKey, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		privateKey, err := keyset.ParsePEM(decodedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return keyset.NewKey(privateKey, algorithm)
+	}
+
+	activeKey, err := parseKey(active)
+	if err != nil {
+		return nil, err
+	}
+
+	var retiredKeys []*keyset.Key
+	for _, encoded := range strings.Split(retired, ",") {
+		if strings.TrimSpace(encoded) == "" {
+			continue
+		}
+
+		retiredKey, err := parseKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		retiredKeys = append(retiredKeys, retiredKey)
+	}
+
+	return keyset.New(activeKey, retiredKeys...)
 }
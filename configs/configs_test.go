@@ -89,3 +89,51 @@ func TestInit(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+		wantErr  bool
+	}{
+		{"0", 0, false},
+		{"5", 5, false},
+		{"-1", 0, true},
+		{"", 0, true},
+		{"five", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			count, err := parseCount(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected error state: got %v, want %v", err, tt.wantErr)
+			}
+
+			if count != tt.expected {
+				t.Errorf("unexpected count: got %v, want %v", count, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProduction(t *testing.T) {
+	tests := []struct {
+		environment string
+		expected    bool
+	}{
+		{"development", false},
+		{"production", true},
+		{"", true},
+		{"develop", true},
+		{"Development", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			if got := production(tt.environment); got != tt.expected {
+				t.Errorf("unexpected production: got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
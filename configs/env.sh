@@ -3,6 +3,7 @@
 release_version=$(cat configs/version.txt | tr -d "[:space:]")
 access_token=$(openssl genrsa 2048 | base64 | tr -d \\n)
 refresh_token=$(openssl genrsa 2048 | base64 | tr -d \\n)
+cursor_secret=$(openssl rand -base64 32)
 ipaddr=$(hostname -I | cut -d' ' -f1)
 
 echo "TZ='America/Manaus'                             # Set system time zone
@@ -15,13 +16,70 @@ API_CACHE='0'                                   # API Cache enable
 API_ENABLE_PREFORK='1'                          # API enable fiber prefork
 API_DEFAULT_SORT='updated_at'                   # API default column sort
 API_DEFAULT_ORDER='desc'                        # API default order
-API_ACCEPT_SKIP_AUTH='1'                        # API accept skip auth header
+API_CURSOR_SECRET='${cursor_secret}'            # API pagination cursors signing secret, empty disables cursors
+API_ENVIRONMENT='development'                   # API environment: development, the only one allowing the development identity, or production
+
+DEV_USER_ID=''                                  # Development identity: user id requests without credentials run as, empty disables it
+DEV_PROFILE_ID=''                               # Development identity profile id, empty keeps the user's own
+DEV_NETWORKS='127.0.0.1/32,::1/128'             # Comma separated networks the development identity is accepted from
 
 ACCESS_TOKEN_EXPIRE='15'                        # Access token expiration time in minutes
 RFRESH_TOKEN_EXPIRE='60'                        # Refresh token expiration time in minutes
+TOKEN_ISSUER='go-api'                           # Tokens iss claim, tokens from other issuers are refused
+TOKEN_AUDIENCE='go-api'                         # Tokens aud claim, tokens for other audiences are refused
+AUTH_COOKIE_DOMAIN=''                           # Domain of the token cookies set for browser clients, empty for the API host
+AUTH_COOKIE_SAMESITE='Lax'                      # SameSite of the token cookies: Lax, Strict or None
+PASSWORD_RESET_EXPIRE='15'                      # Password reset token expiration time in minutes
+PASSWORD_RESET_URL=''                           # Optional reset page link, {token} is replaced by the reset token
+INVITATION_EXPIRE='72'                          # Invitation token expiration time in hours
+INVITATION_URL=''                               # Optional invitation page link, {token} is replaced by the invitation token
+PASSWORD_MIN_LENGTH='8'                         # Password minimum length
+PASSWORD_REQUIRE_UPPER='1'                      # Password requires an upper case letter
+PASSWORD_REQUIRE_LOWER='1'                      # Password requires a lower case letter
+PASSWORD_REQUIRE_DIGIT='1'                      # Password requires a digit
+PASSWORD_REQUIRE_SYMBOL='0'                     # Password requires a symbol
+PASSWORD_HISTORY='5'                            # Previous passwords that can't be reused
+PASSWORD_HASHER='argon2id'                      # Password hash algorithm: argon2id or bcrypt
+BCRYPT_COST='10'                                # Bcrypt cost
+ARGON2_MEMORY='65536'                           # Argon2id memory in KiB
+ARGON2_ITERATIONS='3'                           # Argon2id iterations
+ARGON2_PARALLELISM='2'                          # Argon2id parallelism
+LOGIN_MAX_ATTEMPTS='5'                          # Failed logins before the user is locked
+LOGIN_LOCK_EXPIRE='15'                          # User lock time in minutes
+LOGIN_IP_MAX_ATTEMPTS='20'                      # Failed logins per IP before logins from it are refused
+LOGIN_IP_EXPIRE='15'                            # Window counting failed logins per IP in minutes
+TOTP_ISSUER='Go API'                            # Issuer shown by authenticator apps
+IMPERSONATION_EXPIRE='15'                       # Impersonation token expiration time in minutes
+OIDC_ISSUER=''                                  # OpenID Connect issuer URL, empty disables OIDC login
+OIDC_CLIENT_ID=''                               # OpenID Connect client id
+OIDC_CLIENT_SECRET=''                           # OpenID Connect client secret
+OIDC_REDIRECT_URL=''                            # OpenID Connect redirect URL, the API's /auth/oidc/callback
+OIDC_SCOPES='openid profile email'              # OpenID Connect scopes
+OIDC_LINK_BY_EMAIL='0'                          # Link unknown OIDC users to users with their verified email, no password and no identity
+OIDC_AUTO_PROVISION='0'                         # Create unknown users logging in with OIDC
+OIDC_DEFAULT_PROFILE=''                         # Profile name of created users matching no rule
+OIDC_PROFILE_RULES=''                           # Comma separated 'claim=value:profile' rules, e.g. 'groups=admins:ADMIN'
+LDAP_URL=''                                     # LDAP server URL, e.g. 'ldaps://ldap:636', empty disables LDAP login
+LDAP_START_TLS='0'                              # Upgrade ldap:// connections with StartTLS
+LDAP_BIND_DN=''                                 # Service account DN searching users, empty binds anonymously
+LDAP_BIND_PASSWORD=''                           # Service account password
+LDAP_BASE_DN=''                                 # DN users are searched under
+LDAP_USER_FILTER='(uid={login})'                # User search filter, Active Directory uses '(sAMAccountName={login})'
+LDAP_USERNAME_ATTRIBUTE='uid'                   # Attribute holding the username
+LDAP_EMAIL_ATTRIBUTE='mail'                     # Attribute holding the email
+LDAP_NAME_ATTRIBUTE='cn'                        # Attribute holding the name
+LDAP_GROUP_ATTRIBUTE='memberOf'                 # Attribute holding the group DNs
+LDAP_EMAIL_TRUSTED='0'                          # Trust the email attribute as verified, only if users can't change it
+LDAP_LINK_BY_EMAIL='0'                          # Link unknown LDAP users to users with their trusted email, no password and no identity
+LDAP_AUTO_PROVISION='0'                         # Create unknown users logging in with LDAP
+LDAP_DEFAULT_PROFILE=''                         # Profile name of created users matching no rule
+LDAP_PROFILE_RULES=''                           # Semicolon separated 'group DN:profile' rules, e.g. 'cn=admins,ou=groups,dc=example,dc=com:ADMIN'
 
 ACCESS_TOKEN='${access_token}'                  # Token to encode access token - PRIVATE TOKEN
 RFRESH_TOKEN='${refresh_token}'                 # Token to encode refresh token - PRIVATE TOKEN
+ACCESS_TOKEN_RETIRED=''                         # Comma separated retired access keys, still accepted until their tokens expire
+                                                # Keys may be RSA (RS256), P-256 ECDSA (ES256) or Ed25519 (EdDSA), optionally prefixed by 'ALG:'
+RFRESH_TOKEN_RETIRED=''                         # Comma separated retired refresh keys, still accepted until their tokens expire
 
 POSTGRES_HOST='postgres'                        # Postgres Container HOST
 POSTGRES_PORT='5433'                            # Postgres Container PORT
@@ -34,4 +92,12 @@ MINIO_API_PORT='9004'                           # Minio API PORT
 MINIO_WEB_PORT='9005'                           # Minio WEB PORT
 MINIO_USER='minio'                              # Minio USER
 MINIO_PASS='miniopass'                          # Minio PASS
-MINIO_BUCKET_FILES='api'                        # Minio BUCKET" >.env
+MINIO_BUCKET_FILES='api'                        # Minio BUCKET
+
+MAIL_DRIVER='file'                              # Mail sender: smtp or file
+MAIL_DIR='mails'                                # Directory the file sender writes messages to
+MAIL_HOST=''                                    # SMTP HOST
+MAIL_PORT='587'                                 # SMTP PORT
+MAIL_USER=''                                    # SMTP USER
+MAIL_PASS=''                                    # SMTP PASS
+MAIL_FROM='no-reply@api.local'                  # Mail sender address" >.env
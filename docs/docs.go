@@ -42,6 +42,26 @@ const docTemplate = `{
                 }
             }
         },
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys that verify the access tokens, identified by the kid header of the tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Ping"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_keyset.JWKS"
+                        }
+                    }
+                }
+            }
+        },
         "/auth": {
             "get": {
                 "security": [
@@ -205,6 +225,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -212,16 +244,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/profile": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Get profiles",
+                "description": "Revoke the session of the access token",
                 "consumes": [
                     "application/json"
                 ],
@@ -229,21 +259,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Get profiles",
+                "summary": "User logout",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -254,71 +273,19 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 10,
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "name": "list_root",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "asc",
-                            "desc"
-                        ],
-                        "type": "string",
-                        "default": "desc",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "name",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "updated_at",
-                        "example": "updated_at",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "name": "with_permissions",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ItemsOutputDTO-github_com_raulaguila_go-api_internal_pkg_dto_ProfileOutputDTO"
-                            }
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
                     "500": {
@@ -328,14 +295,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/auth/2fa": {
+            "put": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Insert profile",
+                "description": "Enable two-factor authentication with a code from the enrolled secret and return the recovery codes",
                 "consumes": [
                     "application/json"
                 ],
@@ -343,21 +312,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Insert profile",
+                "summary": "Confirm two-factor authentication",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -370,20 +328,20 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Profile model",
-                        "name": "profile",
+                        "description": "TOTP code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileInputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorCodeInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileOutputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.RecoveryCodesOutputDTO"
                         }
                     },
                     "400": {
@@ -392,6 +350,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -406,13 +376,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Delete profiles by ID",
+                "description": "Generate a new TOTP secret for the authenticated user, enabled only after confirmation",
                 "consumes": [
                     "application/json"
                 ],
@@ -420,21 +390,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Delete profiles by ID",
+                "summary": "Enroll two-factor authentication",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -445,26 +404,29 @@ const docTemplate = `{
                         "description": "Request language",
                         "name": "Accept-Language",
                         "in": "header"
-                    },
-                    {
-                        "description": "Profiles ID",
-                        "name": "ids",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.IDsInputDTO-uint"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorEnrollOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
@@ -476,16 +438,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/profile/{id}": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update profile by ID",
+                "description": "Disable two-factor authentication with a TOTP or recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -493,21 +453,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Profile"
+                    "Auth"
                 ],
-                "summary": "Update profile by ID",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -520,20 +469,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "example": 1,
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Profile model",
-                        "name": "profile",
+                        "description": "TOTP or recovery code",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileInputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorCodeInputDTO"
                         }
                     }
                 ],
@@ -541,7 +482,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileOutputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
                     "400": {
@@ -550,8 +491,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
@@ -565,14 +512,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Get all users",
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Complete a login that requires two-factor authentication",
                 "consumes": [
                     "application/json"
                 ],
@@ -580,21 +522,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Auth"
                 ],
-                "summary": "Get users",
+                "summary": "Verify two-factor authentication",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -607,20 +538,1285 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "name": "level_id",
-                        "in": "query"
+                        "description": "MFA token and TOTP or recovery code",
+                        "name": "challenge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorVerifyInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.AuthOutputDTO"
+                        }
                     },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/impersonate/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a short-lived access token to act as the user, recorded in the audit trail. It can't be refreshed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Impersonate user",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.AuthOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/keys": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the active API keys of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
+                ],
+                "summary": "Get API keys",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.APIKeyOutputDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Create an API key for the authenticated user. The key is only returned by this request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
+                ],
+                "summary": "Insert API key",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "API key model",
+                        "name": "apikey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.APIKeyInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.APIKeyOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke one API key of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Key"
+                ],
+                "summary": "Revoke API key by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/ldap/link": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Link the directory entry of the credentials to the authenticated user, so it may log in with them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Link LDAP identity",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Directory credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.LDAPLinkInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc": {
+            "get": {
+                "description": "Redirect to the identity provider to log in",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "OpenID Connect login",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Expire token",
+                        "name": "expire",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Complete an OpenID Connect login, linking or provisioning the user, or link the identity when started by /auth/oidc/link",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "OpenID Connect callback",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.AuthOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/link": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Start an OpenID Connect login that links the provider's identity to the authenticated user. Send the user to the returned URL; the callback then links the identity instead of logging in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Link OpenID Connect identity",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.OIDCLinkOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the active sessions of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get sessions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.SessionOutputDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke every session of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "User logout everywhere",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke one session of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke session by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get profiles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get profiles",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "After and Before are cursors from a previous page, read instead of Page. WithCount asks for the total\nof rows when reading by cursor, as pages are always counted.",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "list_root",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "updated_at",
+                        "example": "name,-updated_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "with_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "with_permissions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ItemsOutputDTO-github_com_raulaguila_go-api_internal_pkg_dto_ProfileOutputDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Insert profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Insert profile",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete profiles by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Delete profiles by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Profiles ID",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.IDsInputDTO-uint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get the permissions profiles may be granted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Get permissions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.PermissionOutputDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update profile by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Profile"
+                ],
+                "summary": "Update profile by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile model",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ProfileOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "After and Before are cursors from a previous page, read instead of Page. WithCount asks for the total\nof rows when reading by cursor, as pages are always counted.",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "name": "level_id",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
                         "default": 10,
                         "name": "limit",
                         "in": "query"
@@ -649,27 +1845,242 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "contains",
+                            "fulltext",
+                            "trigram"
+                        ],
+                        "type": "string",
+                        "default": "contains",
+                        "name": "search_mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "updated_at",
+                        "example": "name,-updated_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "with_count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ItemsOutputDTO-github_com_raulaguila_go-api_internal_pkg_dto_UserOutputDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Insert user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Insert user",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User model",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserInputDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Delete user by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.IDsInputDTO-uint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/invite": {
+            "put": {
+                "description": "Set the first password of an invited user using the mailed invitation token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Accept invitation",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en-US",
+                            "pt-BR"
+                        ],
                         "type": "string",
-                        "default": "updated_at",
-                        "example": "updated_at",
-                        "name": "sort",
-                        "in": "query"
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
+                        "in": "header"
                     },
                     {
-                        "type": "boolean",
-                        "example": false,
-                        "name": "status",
-                        "in": "query"
+                        "description": "Invitation model",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.InvitationInputDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.ItemsOutputDTO-github_com_raulaguila_go-api_internal_pkg_dto_UserOutputDTO"
-                            }
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
                     "500": {
@@ -679,14 +2090,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/user/pass": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Insert user",
+                "description": "Log the user out of every session and mail a password reset token",
                 "consumes": [
                     "application/json"
                 ],
@@ -696,19 +2109,66 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Insert user",
+                "summary": "Reset user password by email",
                 "parameters": [
                     {
                         "enum": [
-                            true,
-                            false
+                            "en-US",
+                            "pt-BR"
                         ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
                         "in": "header"
                     },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/pass/reset": {
+            "put": {
+                "description": "Set a new password using a password reset token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Confirm password reset",
+                "parameters": [
                     {
                         "enum": [
                             "en-US",
@@ -721,20 +2181,20 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "User model",
-                        "name": "user",
+                        "description": "Reset model",
+                        "name": "reset",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserInputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.PasswordResetInputDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserOutputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
                     "400": {
@@ -743,12 +2203,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -757,13 +2211,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Delete user by ID",
+            "post": {
+                "description": "Mail a single use password reset token if the email belongs to an active user",
                 "consumes": [
                     "application/json"
                 ],
@@ -773,19 +2222,8 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Request password reset",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -798,21 +2236,24 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Reset request model",
+                        "name": "email",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.IDsInputDTO-uint"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.PasswordResetRequestDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
@@ -826,9 +2267,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/pass": {
+        "/user/{id}": {
             "put": {
-                "description": "Set user password by ID",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Update user by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -838,19 +2284,8 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Set user password by ID",
+                "summary": "Update user by ID",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -863,26 +2298,41 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "format": "email",
-                        "description": "User email",
-                        "name": "email",
-                        "in": "query",
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Password model",
-                        "name": "password",
+                        "description": "User model",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.PasswordInputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserInputDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -897,14 +2347,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/user/{id}/2fa": {
             "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Reset user password by ID",
+                "description": "Disable two-factor authentication of a user that lost the authenticator",
                 "consumes": [
                     "application/json"
                 ],
@@ -914,19 +2366,80 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Reset user password by ID",
+                "summary": "Reset user two-factor authentication by ID",
                 "parameters": [
                     {
                         "enum": [
-                            true,
-                            false
+                            "en-US",
+                            "pt-BR"
                         ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
+                        "type": "string",
+                        "default": "en-US",
+                        "description": "Request language",
+                        "name": "Accept-Language",
                         "in": "header"
                     },
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/invite": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Mail a new invitation to a user without password, invalidating the previous ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Resend invitation by ID",
+                "parameters": [
                     {
                         "enum": [
                             "en-US",
@@ -939,16 +2452,31 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "type": "string",
-                        "description": "User email",
-                        "name": "email",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -965,14 +2493,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/{id}": {
-            "put": {
+        "/user/{id}/lock": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Update user by ID",
+                "description": "Clear the failed logins of a user, lifting its lock",
                 "consumes": [
                     "application/json"
                 ],
@@ -982,19 +2510,8 @@ const docTemplate = `{
                 "tags": [
                     "User"
                 ],
-                "summary": "Update user by ID",
+                "summary": "Unlock user by ID",
                 "parameters": [
-                    {
-                        "enum": [
-                            true,
-                            false
-                        ],
-                        "type": "boolean",
-                        "default": true,
-                        "description": "Skip auth",
-                        "name": "X-Skip-Auth",
-                        "in": "header"
-                    },
                     {
                         "enum": [
                             "en-US",
@@ -1007,28 +2524,18 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "minimum": 1,
                         "type": "integer",
-                        "example": 1,
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "User model",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserInputDTO"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_dto.UserOutputDTO"
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
                     "400": {
@@ -1037,6 +2544,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_raulaguila_go-api_internal_pkg_HTTPResponse.Response"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1068,9 +2581,67 @@ const docTemplate = `{
                 "object": {}
             }
         },
+        "github_com_raulaguila_go-api_internal_pkg_dto.APIKeyInputDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly-export"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.APIKeyOutputDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "type": "string",
+                    "example": "gak_3f9a2c1b_Xb8rH3c1bJ0yZfQhPqUuKdVwN2mTzA5sR7eGkLiOj4o"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly-export"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "3f9a2c1b"
+                }
+            }
+        },
         "github_com_raulaguila_go-api_internal_pkg_dto.AuthInputDTO": {
             "type": "object",
             "properties": {
+                "cookie": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "expiration": {
                     "type": "boolean",
                     "default": true,
@@ -1078,7 +2649,7 @@ const docTemplate = `{
                 },
                 "login": {
                     "type": "string",
-                    "example": "00000000"
+                    "example": "admin"
                 },
                 "password": {
                     "type": "string",
@@ -1092,6 +2663,16 @@ const docTemplate = `{
                 "accesstoken": {
                     "type": "string"
                 },
+                "csrf_token": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
                 "refreshtoken": {
                     "type": "string"
                 },
@@ -1111,6 +2692,23 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_raulaguila_go-api_internal_pkg_dto.InvitationInputDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "password_confirm": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJSUzI1NiIsImtpZCI6IjEifQ..."
+                }
+            }
+        },
         "github_com_raulaguila_go-api_internal_pkg_dto.ItemsOutputDTO-github_com_raulaguila_go-api_internal_pkg_dto_ProfileOutputDTO": {
             "type": "object",
             "properties": {
@@ -1139,15 +2737,43 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_raulaguila_go-api_internal_pkg_dto.LDAPLinkInputDTO": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "example": "john.cena"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.OIDCLinkOutputDTO": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string",
+                    "example": "https://idp.example.com/authorize?client_id=go-api\u0026response_type=code"
+                }
+            }
+        },
         "github_com_raulaguila_go-api_internal_pkg_dto.PaginationDTO": {
             "type": "object",
             "properties": {
                 "current_page": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "page_size": {
                     "type": "integer"
                 },
+                "prev_cursor": {
+                    "type": "string"
+                },
                 "total_items": {
                     "type": "integer"
                 },
@@ -1156,7 +2782,7 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_raulaguila_go-api_internal_pkg_dto.PasswordInputDTO": {
+        "github_com_raulaguila_go-api_internal_pkg_dto.PasswordResetInputDTO": {
             "type": "object",
             "properties": {
                 "password": {
@@ -1166,6 +2792,40 @@ const docTemplate = `{
                 "password_confirm": {
                     "type": "string",
                     "example": "secret"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "Xb8rH3c1bJ0yZfQhPqUuKdVwN2mTzA5sR7eGkLiOj4o"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.PasswordResetRequestDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.cena@email.com"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.PermissionOutputDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "List and view users."
+                },
+                "group": {
+                    "type": "string",
+                    "example": "users"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Read users"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "users:read"
                 }
             }
         },
@@ -1176,6 +2836,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "ADMIN"
                 },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "permissions": {
                     "type": "array",
                     "items": {
@@ -1187,6 +2851,12 @@ const docTemplate = `{
         "github_com_raulaguila_go-api_internal_pkg_dto.ProfileOutputDTO": {
             "type": "object",
             "properties": {
+                "effective_permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -1195,6 +2865,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "ADMIN"
                 },
+                "parent_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "permissions": {
                     "type": "array",
                     "items": {
@@ -1203,6 +2877,92 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_raulaguila_go-api_internal_pkg_dto.RecoveryCodesOutputDTO": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "abcd-efgh"
+                    ]
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.SessionOutputDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f8a3b9e-8f43-4c49-9c1a-0d5b2a1e7c11"
+                },
+                "impersonated": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorCodeInputDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorEnrollOutputDTO": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                },
+                "uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Go%20API:admin?algorithm=SHA1\u0026digits=6\u0026issuer=Go+API\u0026period=30\u0026secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_internal_pkg_dto.TwoFactorVerifyInputDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "cookie": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_raulaguila_go-api_internal_pkg_dto.UserInputDTO": {
             "type": "object",
             "properties": {
@@ -1224,7 +2984,7 @@ const docTemplate = `{
                 },
                 "username": {
                     "type": "string",
-                    "example": "51515151"
+                    "example": "john.cena"
                 }
             }
         },
@@ -1239,10 +2999,23 @@ const docTemplate = `{
                     "type": "string",
                     "example": "john.cena@email.com"
                 },
+                "email_verified": {
+                    "type": "boolean",
+                    "example": true
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
+                "invitation_sent": {
+                    "description": "InvitationSent tells, on creation, whether the invitation could be mailed.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "name": {
                     "type": "string",
                     "example": "John Cena"
@@ -1257,6 +3030,53 @@ const docTemplate = `{
                 "status": {
                     "type": "boolean",
                     "example": true
+                },
+                "two_factor": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_keyset.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_raulaguila_go-api_pkg_keyset.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_raulaguila_go-api_pkg_keyset.JWK"
+                    }
                 }
             }
         }
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/configs"
+	"github.com/raulaguila/go-api/internal/api/rest/middleware"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/secret"
+)
+
+// routeRefresh names the token refresh route, the only path the refresh token cookie is sent to.
+const routeRefresh string = "Refresh"
+
+func authCookie(c *fiber.Ctx, name, value string, maxAge time.Duration, httpOnly bool) *fiber.Cookie {
+	path := "/"
+	if name == middleware.CookieRefreshToken {
+		path = c.App().GetRoute(routeRefresh).Path
+	}
+
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   configs.AuthCookieDomain,
+		MaxAge:   int(maxAge.Seconds()),
+		Secure:   true,
+		HTTPOnly: httpOnly,
+		SameSite: configs.AuthCookieSameSite,
+	}
+}
+
+// setAuthCookies moves the tokens of the response to HttpOnly cookies, along with a new CSRF token, which
+// is also returned for clients that can't read the API's cookies.
+func setAuthCookies(c *fiber.Ctx, response *dto.AuthOutputDTO) error {
+	if response.AccessToken == "" {
+		return nil
+	}
+
+	csrfToken, err := secret.NewToken(32)
+	if err != nil {
+		return err
+	}
+
+	c.Cookie(authCookie(c, middleware.CookieAccessToken, response.AccessToken, configs.AccessExpiration, true))
+	c.Cookie(authCookie(c, middleware.CookieRefreshToken, response.RefreshToken, configs.RefreshExpiration, true))
+	c.Cookie(authCookie(c, middleware.CookieCSRFToken, csrfToken, configs.RefreshExpiration, false))
+
+	response.AccessToken, response.RefreshToken, response.CSRFToken = "", "", csrfToken
+	return nil
+}
+
+func clearAuthCookies(c *fiber.Ctx) {
+	for _, name := range []string{middleware.CookieAccessToken, middleware.CookieRefreshToken, middleware.CookieCSRFToken} {
+		cookie := authCookie(c, name, "", 0, true)
+		cookie.Expires = time.Unix(0, 0)
+		c.Cookie(cookie)
+	}
+}
@@ -1,20 +1,51 @@
 package handler
 
 import (
+	"strings"
+
 	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
 	"github.com/raulaguila/go-api/internal/api/rest/middleware"
+	"github.com/raulaguila/go-api/internal/api/rest/middleware/datatransferobject"
 	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/packhub"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
+var middlewareTwoFactorCodeDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.TwoFactorCodeInputDTO{},
+})
+
+var middlewareLDAPLinkDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.LDAPLinkInputDTO{},
+})
+
+var middlewareTwoFactorVerifyDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.TwoFactorVerifyInputDTO{},
+})
+
+// cookieOIDCState keeps the OpenID Connect login state token between the redirect to the provider and its callback,
+// and cookieOIDCLinkState the one of a login linking the identity to the logged in user.
+const (
+	cookieOIDCState     string = "oidc_state"
+	cookieOIDCLinkState string = "oidc_link_state"
+)
+
 type AuthHandler struct {
-	service      domain.AuthService
-	handlerError func(*fiber.Ctx, error) error
+	service             domain.AuthService
+	handlerError        func(*fiber.Ctx, error) error
+	sessionHandlerError func(*fiber.Ctx, error) error
 }
 
 func NewAuthHandler(route fiber.Router, service domain.AuthService) {
@@ -22,16 +53,57 @@ func NewAuthHandler(route fiber.Router, service domain.AuthService) {
 		service: service,
 		handlerError: newErrorHandler(map[string]map[error][]any{
 			"*": {
-				utils.ErrDisabledUser:       []any{fiber.StatusUnauthorized, "disabledUser"},
-				utils.ErrInvalidCredentials: []any{fiber.StatusUnauthorized, "incorrectCredentials"},
-				gorm.ErrRecordNotFound:      []any{fiber.StatusNotFound, "userNotFound"},
+				utils.ErrDisabledUser:             []any{fiber.StatusUnauthorized, "disabledUser"},
+				utils.ErrInvalidCredentials:       []any{fiber.StatusUnauthorized, "incorrectCredentials"},
+				utils.ErrLoginThrottled:           []any{fiber.StatusTooManyRequests, "loginThrottled"},
+				utils.ErrLockedUser:               []any{fiber.StatusLocked, "lockedUser"},
+				utils.ErrInvalidToken:             []any{fiber.StatusUnauthorized, "invalidSession"},
+				utils.ErrTokenReused:              []any{fiber.StatusUnauthorized, "invalidSession"},
+				utils.ErrInvalidTwoFactorCode:     []any{fiber.StatusUnauthorized, "invalidTwoFactorCode"},
+				utils.ErrTwoFactorEnabled:         []any{fiber.StatusConflict, "twoFactorEnabled"},
+				utils.ErrTwoFactorDisabled:        []any{fiber.StatusBadRequest, "twoFactorNotEnabled"},
+				utils.ErrInvalidID:                []any{fiber.StatusBadRequest, "invalidID"},
+				utils.ErrOIDCDisabled:             []any{fiber.StatusNotFound, "oidcDisabled"},
+				utils.ErrIdentityNotLinked:        []any{fiber.StatusForbidden, "identityNotLinked"},
+				utils.ErrIdentityInUse:            []any{fiber.StatusConflict, "identityInUse"},
+				utils.ErrLDAPDisabled:             []any{fiber.StatusNotFound, "ldapDisabled"},
+				utils.ErrAuthenticatorUnavailable: []any{fiber.StatusBadGateway, "ldapUnavailable"},
+				utils.ErrImpersonationNotAllowed:  []any{fiber.StatusForbidden, "impersonationNotAllowed"},
+				oidc.ErrExchange:                  []any{fiber.StatusUnauthorized, "oidcLoginFailed"},
+				oidc.ErrInvalidIDToken:            []any{fiber.StatusUnauthorized, "oidcLoginFailed"},
+				oidc.ErrDiscovery:                 []any{fiber.StatusBadGateway, "oidcUnavailable"},
+				gorm.ErrRecordNotFound:            []any{fiber.StatusNotFound, "userNotFound"},
+			},
+		}),
+		sessionHandlerError: newErrorHandler(map[string]map[error][]any{
+			"*": {
+				utils.ErrInvalidToken:  []any{fiber.StatusUnauthorized, "invalidSession"},
+				utils.ErrInvalidID:     []any{fiber.StatusBadRequest, "invalidID"},
+				gorm.ErrRecordNotFound: []any{fiber.StatusNotFound, "sessionNotFound"},
 			},
 		}),
 	}
 
 	route.Post("", handler.login)
 	route.Get("", middleware.MidAccess, handler.me)
-	route.Put("", middleware.MidRefresh, handler.refresh)
+	route.Put("", middleware.MidRefresh, handler.refresh).Name(routeRefresh)
+	route.Delete("", middleware.MidAccess, handler.logout)
+
+	route.Get("/sessions", middleware.MidAccess, middleware.RequireSession, handler.getSessions)
+	route.Delete("/sessions", middleware.MidAccess, middleware.RequireSession, handler.logoutAll)
+	route.Delete("/sessions/:"+utils.ParamID, middleware.MidAccess, middleware.RequireSession, middlewareIDStringDTO, handler.revokeSession)
+
+	route.Post("/2fa", middleware.MidAccess, middleware.RequireSession, handler.enrollTwoFactor)
+	route.Put("/2fa", middleware.MidAccess, middleware.RequireSession, middlewareTwoFactorCodeDTO, handler.confirmTwoFactor)
+	route.Delete("/2fa", middleware.MidAccess, middleware.RequireSession, middlewareTwoFactorCodeDTO, handler.disableTwoFactor)
+	route.Post("/2fa/verify", middlewareTwoFactorVerifyDTO, handler.verifyTwoFactor)
+
+	route.Get("/oidc", handler.oidcAuthorization)
+	route.Get("/oidc/callback", handler.oidcCallback)
+	route.Post("/oidc/link", middleware.MidAccess, middleware.RequireSession, handler.oidcLink)
+	route.Post("/ldap/link", middleware.MidAccess, middleware.RequireSession, middlewareLDAPLinkDTO, handler.ldapLink)
+
+	route.Post("/impersonate/:"+utils.ParamID, middleware.MidAccess, middleware.RequireSession, middleware.Permission(domain.PermUsersImpersonate), middlewareIDIntDTO, handler.impersonate)
 }
 
 // login godoc
@@ -44,6 +116,8 @@ func NewAuthHandler(route fiber.Router, service domain.AuthService) {
 // @Param        credentials		body	dto.AuthInputDTO	true	"Credentials model"
 // @Success      200  {object}  	dto.AuthOutputDTO
 // @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      423  {object}  	HTTPResponse.Response
+// @Failure      429  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /auth [post]
 func (s *AuthHandler) login(c *fiber.Ctx) error {
@@ -52,11 +126,18 @@ func (s *AuthHandler) login(c *fiber.Ctx) error {
 		return HTTPResponse.New(c, fiber.StatusBadRequest, fiberi18n.MustLocalize(c, "invalidData"), nil)
 	}
 
+	credentials.IP, credentials.UserAgent = c.IP(), c.Get(fiber.HeaderUserAgent)
 	authResponse, err := s.service.Login(c.Context(), credentials)
 	if err != nil {
 		return s.handlerError(c, err)
 	}
 
+	if credentials.Cookie {
+		if err := setAuthCookies(c, authResponse); err != nil {
+			return s.handlerError(c, err)
+		}
+	}
+
 	return c.Status(fiber.StatusOK).JSON(authResponse)
 }
 
@@ -92,5 +173,384 @@ func (s *AuthHandler) me(c *fiber.Ctx) error {
 // @Router       /auth [put]
 func (s *AuthHandler) refresh(c *fiber.Ctx) error {
 	expire := c.Query("expire", "true") == "true"
-	return c.Status(fiber.StatusOK).JSON(s.service.Refresh(c.Locals(utils.LocalUser).(*domain.User), expire))
+	claims := c.Locals(utils.LocalClaims).(*domain.Claims)
+	session, _ := c.Locals(utils.LocalSession).(*domain.Session)
+
+	authResponse, err := s.service.Refresh(c.Context(), c.Locals(utils.LocalUser).(*domain.User), session, claims.ID, expire)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if middleware.CookieAuth(c) {
+		if err := setAuthCookies(c, authResponse); err != nil {
+			return s.handlerError(c, err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// logout godoc
+// @Summary      User logout
+// @Description  Revoke the session of the access token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth [delete]
+// @Security	 Bearer
+func (s *AuthHandler) logout(c *fiber.Ctx) error {
+	session, _ := c.Locals(utils.LocalSession).(*domain.Session)
+	if err := s.service.Logout(c.Context(), session); err != nil {
+		return s.sessionHandlerError(c, err)
+	}
+
+	if middleware.CookieAuth(c) {
+		clearAuthCookies(c)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "loggedOut"), nil)
+}
+
+// getSessions godoc
+// @Summary      Get sessions
+// @Description  Get the active sessions of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.SessionOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/sessions [get]
+// @Security	 Bearer
+func (s *AuthHandler) getSessions(c *fiber.Ctx) error {
+	session, _ := c.Locals(utils.LocalSession).(*domain.Session)
+	sessions, err := s.service.GetSessions(c.Context(), c.Locals(utils.LocalUser).(*domain.User), session)
+	if err != nil {
+		return s.sessionHandlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(sessions)
+}
+
+// logoutAll godoc
+// @Summary      User logout everywhere
+// @Description  Revoke every session of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/sessions [delete]
+// @Security	 Bearer
+func (s *AuthHandler) logoutAll(c *fiber.Ctx) error {
+	if err := s.service.LogoutAll(c.Context(), c.Locals(utils.LocalUser).(*domain.User)); err != nil {
+		return s.sessionHandlerError(c, err)
+	}
+
+	if middleware.CookieAuth(c) {
+		clearAuthCookies(c)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "loggedOut"), nil)
+}
+
+// revokeSession godoc
+// @Summary      Revoke session by ID
+// @Description  Revoke one session of the authenticated user
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path	string				true	"Session ID"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/sessions/{id} [delete]
+// @Security	 Bearer
+func (s *AuthHandler) revokeSession(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[string])
+	if err := s.service.RevokeSession(c.Context(), c.Locals(utils.LocalUser).(*domain.User), id.ID); err != nil {
+		return s.sessionHandlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "sessionRevoked"), nil)
+}
+
+// enrollTwoFactor godoc
+// @Summary      Enroll two-factor authentication
+// @Description  Generate a new TOTP secret for the authenticated user, enabled only after confirmation
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	dto.TwoFactorEnrollOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      409  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/2fa [post]
+// @Security	 Bearer
+func (s *AuthHandler) enrollTwoFactor(c *fiber.Ctx) error {
+	enrollment, err := s.service.EnrollTwoFactor(c.Context(), c.Locals(utils.LocalUser).(*domain.User))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(enrollment)
+}
+
+// confirmTwoFactor godoc
+// @Summary      Confirm two-factor authentication
+// @Description  Enable two-factor authentication with a code from the enrolled secret and return the recovery codes
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				body	dto.TwoFactorCodeInputDTO	true	"TOTP code"
+// @Success      200  {object}  	dto.RecoveryCodesOutputDTO
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      409  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/2fa [put]
+// @Security	 Bearer
+func (s *AuthHandler) confirmTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(utils.LocalDTO).(*dto.TwoFactorCodeInputDTO)
+	recoveryCodes, err := s.service.ConfirmTwoFactor(c.Context(), c.Locals(utils.LocalUser).(*domain.User), packhub.PointerValue(input.Code, ""))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(recoveryCodes)
+}
+
+// disableTwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Disable two-factor authentication with a TOTP or recovery code
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				body	dto.TwoFactorCodeInputDTO	true	"TOTP or recovery code"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/2fa [delete]
+// @Security	 Bearer
+func (s *AuthHandler) disableTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(utils.LocalDTO).(*dto.TwoFactorCodeInputDTO)
+	if err := s.service.DisableTwoFactor(c.Context(), c.Locals(utils.LocalUser).(*domain.User), packhub.PointerValue(input.Code, "")); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "twoFactorDisabled"), nil)
+}
+
+// verifyTwoFactor godoc
+// @Summary      Verify two-factor authentication
+// @Description  Complete a login that requires two-factor authentication
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        challenge			body	dto.TwoFactorVerifyInputDTO	true	"MFA token and TOTP or recovery code"
+// @Success      200  {object}  	dto.AuthOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      423  {object}  	HTTPResponse.Response
+// @Failure      429  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/2fa/verify [post]
+func (s *AuthHandler) verifyTwoFactor(c *fiber.Ctx) error {
+	input := c.Locals(utils.LocalDTO).(*dto.TwoFactorVerifyInputDTO)
+	input.IP, input.UserAgent = c.IP(), c.Get(fiber.HeaderUserAgent)
+
+	authResponse, err := s.service.VerifyTwoFactor(c.Context(), input)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	if input.Cookie {
+		if err := setAuthCookies(c, authResponse); err != nil {
+			return s.handlerError(c, err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// oidcAuthorization godoc
+// @Summary      OpenID Connect login
+// @Description  Redirect to the identity provider to log in
+// @Tags         Auth
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        expire				query	bool				false	"Expire token"
+// @Success      302
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      502  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/oidc [get]
+func (s *AuthHandler) oidcAuthorization(c *fiber.Ctx) error {
+	authURL, stateToken, err := s.service.OIDCAuthorization(c.Context(), c.Query("expire", "true") == "true")
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	c.Cookie(oidcStateCookie(c, cookieOIDCState, stateToken, c.Path()))
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// oidcStateCookie keeps the state token for the callback, under path. It's sent along the provider's
+// redirect back, so it can't be strict.
+func oidcStateCookie(c *fiber.Ctx, name, stateToken, path string) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    stateToken,
+		Path:     path,
+		MaxAge:   int(domain.OIDCStateExpiration.Seconds()),
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+}
+
+// oidcLink godoc
+// @Summary      Link OpenID Connect identity
+// @Description  Start an OpenID Connect login that links the provider's identity to the authenticated user. Send the user to the returned URL; the callback then links the identity instead of logging in.
+// @Tags         Auth
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {object}  	dto.OIDCLinkOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      502  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/oidc/link [post]
+// @Security	 Bearer
+func (s *AuthHandler) oidcLink(c *fiber.Ctx) error {
+	authURL, stateToken, err := s.service.OIDCLinkAuthorization(c.Context(), c.Locals(utils.LocalUser).(*domain.User))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	c.Cookie(oidcStateCookie(c, cookieOIDCLinkState, stateToken, strings.TrimSuffix(c.Path(), "/link")))
+	return c.Status(fiber.StatusOK).JSON(&dto.OIDCLinkOutputDTO{URL: authURL})
+}
+
+// oidcCallback godoc
+// @Summary      OpenID Connect callback
+// @Description  Complete an OpenID Connect login, linking or provisioning the user, or link the identity when started by /auth/oidc/link
+// @Tags         Auth
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        code				query	string				true	"Authorization code"
+// @Param        state				query	string				true	"State"
+// @Success      200  {object}  	dto.AuthOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      409  {object}  	HTTPResponse.Response
+// @Failure      502  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/oidc/callback [get]
+func (s *AuthHandler) oidcCallback(c *fiber.Ctx) error {
+	input := &dto.OIDCCallbackInputDTO{
+		Code:       c.Query("code"),
+		State:      c.Query("state"),
+		StateToken: c.Cookies(cookieOIDCState),
+		IP:         c.IP(),
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+	}
+	linkStateToken := c.Cookies(cookieOIDCLinkState)
+	c.ClearCookie(cookieOIDCState, cookieOIDCLinkState)
+
+	// The provider reports a refused or failed login with an error instead of a code.
+	if c.Query("error") != "" || input.Code == "" {
+		return HTTPResponse.New(c, fiber.StatusUnauthorized, fiberi18n.MustLocalize(c, "oidcLoginFailed"), nil)
+	}
+
+	if linkStateToken != "" {
+		input.StateToken = linkStateToken
+		if err := s.service.OIDCLink(c.Context(), input); err != nil {
+			return s.handlerError(c, err)
+		}
+
+		return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "identityLinked"), nil)
+	}
+
+	authResponse, err := s.service.OIDCLogin(c.Context(), input)
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
+}
+
+// ldapLink godoc
+// @Summary      Link LDAP identity
+// @Description  Link the directory entry of the credentials to the authenticated user, so it may log in with them
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string					false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        credentials		body	dto.LDAPLinkInputDTO	true	"Directory credentials"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      409  {object}  	HTTPResponse.Response
+// @Failure      429  {object}  	HTTPResponse.Response
+// @Failure      502  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/ldap/link [post]
+// @Security	 Bearer
+func (s *AuthHandler) ldapLink(c *fiber.Ctx) error {
+	input := c.Locals(utils.LocalDTO).(*dto.LDAPLinkInputDTO)
+	input.IP = c.IP()
+	if err := s.service.LDAPLink(c.Context(), c.Locals(utils.LocalUser).(*domain.User), input); err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "identityLinked"), nil)
+}
+
+// impersonate godoc
+// @Summary      Impersonate user
+// @Description  Get a short-lived access token to act as the user, recorded in the audit trail. It can't be refreshed.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path	dto.IDFilter[uint]	true	"User ID"
+// @Success      200  {object}  	dto.AuthOutputDTO
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/impersonate/{id} [post]
+// @Security	 Bearer
+func (s *AuthHandler) impersonate(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[uint])
+	authResponse, err := s.service.Impersonate(c.Context(), c.Locals(utils.LocalUser).(*domain.User), id.ID, c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return s.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(authResponse)
 }
@@ -43,6 +43,15 @@ var middlewareIDIntDTO = datatransferobject.New(datatransferobject.Config{
 	},
 })
 
+var middlewareIDStringDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalID,
+	OnLookup:   datatransferobject.Params,
+	Model:      &dto.IDFilter[string]{},
+	ErrorHandler: func(c *fiber.Ctx, err error) error {
+		return HTTPResponse.New(c, fiber.StatusBadRequest, "invalidID", nil)
+	},
+})
+
 var middlewareIDsIntDTO = datatransferobject.New(datatransferobject.Config{
 	ContextKey: utils.LocalID,
 	OnLookup:   datatransferobject.Body,
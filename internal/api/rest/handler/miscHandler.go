@@ -4,15 +4,20 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/pkg/keyset"
 )
 
-func NewMiscHandler(miscRoute fiber.Router) {
-	handler := &MiscHandler{}
+func NewMiscHandler(miscRoute fiber.Router, accessKeys *keyset.KeySet) {
+	handler := &MiscHandler{accessKeys: accessKeys}
 
 	miscRoute.Get("", handler.healthCheck).Name("Root")
+	miscRoute.Get("/.well-known/jwks.json", handler.jwks).Name("JWKS")
 }
 
-type MiscHandler struct{}
+type MiscHandler struct {
+	accessKeys *keyset.KeySet
+}
 
 // healthCheck godoc
 // @Summary      Ping Pong
@@ -26,3 +31,15 @@ func (h *MiscHandler) healthCheck(c *fiber.Ctx) error {
 		"time": time.Now(),
 	})
 }
+
+// jwks godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys that verify the access tokens, identified by the kid header of the tokens
+// @Tags         Ping
+// @Produce      json
+// @Success      200  {object}   keyset.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *MiscHandler) jwks(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.accessKeys.JWKS())
+}
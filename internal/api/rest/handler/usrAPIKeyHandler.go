@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/api/rest/middleware"
+	"github.com/raulaguila/go-api/internal/api/rest/middleware/datatransferobject"
+	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+var middlewareAPIKeyDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.APIKeyInputDTO{},
+})
+
+type apiKeyHandler struct {
+	service      domain.APIKeyService
+	handlerError func(*fiber.Ctx, error) error
+}
+
+func NewAPIKeyHandler(route fiber.Router, service domain.APIKeyService) {
+	handler := &apiKeyHandler{
+		service: service,
+		handlerError: newErrorHandler(map[string]map[error][]any{
+			"*": {
+				utils.ErrInvalidID:            []any{fiber.StatusBadRequest, "invalidID"},
+				utils.ErrInvalidAPIKey:        []any{fiber.StatusBadRequest, "invalidData"},
+				utils.ErrPermissionNotGranted: []any{fiber.StatusBadRequest, "permissionNotGranted"},
+				gorm.ErrRecordNotFound:        []any{fiber.StatusNotFound, "apiKeyNotFound"},
+			},
+		}),
+	}
+
+	route.Use(middleware.MidAccess, middleware.RequireSession)
+
+	route.Get("", handler.getAPIKeys)
+	route.Post("", middlewareAPIKeyDTO, handler.createAPIKey)
+	route.Delete("/:"+utils.ParamID, middlewareIDIntDTO, handler.revokeAPIKey)
+}
+
+// getAPIKeys godoc
+// @Summary      Get API keys
+// @Description  Get the active API keys of the authenticated user
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.APIKeyOutputDTO
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/keys [get]
+// @Security	 Bearer
+func (h *apiKeyHandler) getAPIKeys(c *fiber.Ctx) error {
+	apiKeys, err := h.service.GetAPIKeys(c.Context(), c.Locals(utils.LocalUser).(*domain.User))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(apiKeys)
+}
+
+// createAPIKey godoc
+// @Summary      Insert API key
+// @Description  Create an API key for the authenticated user. The key is only returned by this request
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        apikey				body	dto.APIKeyInputDTO	true	"API key model"
+// @Success      201  {object}  	dto.APIKeyOutputDTO
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/keys [post]
+// @Security	 Bearer
+func (h *apiKeyHandler) createAPIKey(c *fiber.Ctx) error {
+	apiKey, err := h.service.CreateAPIKey(c.Context(), c.Locals(utils.LocalUser).(*domain.User), c.Locals(utils.LocalDTO).(*dto.APIKeyInputDTO))
+	if err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(apiKey)
+}
+
+// revokeAPIKey godoc
+// @Summary      Revoke API key by ID
+// @Description  Revoke one API key of the authenticated user
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path	int					true	"API key ID"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      401  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /auth/keys/{id} [delete]
+// @Security	 Bearer
+func (h *apiKeyHandler) revokeAPIKey(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[uint])
+	if err := h.service.RevokeAPIKey(c.Context(), c.Locals(utils.LocalUser).(*domain.User), id.ID); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "apiKeyRevoked"), nil)
+}
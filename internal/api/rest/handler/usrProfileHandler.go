@@ -11,6 +11,7 @@ import (
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
 	"github.com/raulaguila/go-api/pkg/pgerror"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
@@ -33,20 +34,27 @@ func NewProfileHandler(route fiber.Router, service domain.ProfileService) {
 				pgerror.ErrForeignKeyViolated: []any{fiber.StatusBadRequest, "profileUsed"},
 			},
 			"*": {
-				utils.ErrInvalidID:         []any{fiber.StatusBadRequest, "invalidID"},
-				pgerror.ErrUndefinedColumn: []any{fiber.StatusBadRequest, "undefinedColumn"},
-				pgerror.ErrDuplicatedKey:   []any{fiber.StatusConflict, "profileRegistered"},
-				gorm.ErrRecordNotFound:     []any{fiber.StatusNotFound, "profileNotFound"},
+				utils.ErrInvalidID:             []any{fiber.StatusBadRequest, "invalidID"},
+				utils.ErrUnknownPermission:     []any{fiber.StatusBadRequest, "unknownPermission"},
+				utils.ErrProfileParentNotFound: []any{fiber.StatusBadRequest, "profileParentNotFound"},
+				utils.ErrProfileCycle:          []any{fiber.StatusBadRequest, "profileCycle"},
+				pgerror.ErrUndefinedColumn:     []any{fiber.StatusBadRequest, "undefinedColumn"},
+				pgfilter.ErrInvalidSort:        []any{fiber.StatusBadRequest, "invalidSort"},
+				pgfilter.ErrInvalidFilter:      []any{fiber.StatusBadRequest, "invalidFilter"},
+				pgfilter.ErrInvalidCursor:      []any{fiber.StatusBadRequest, "invalidCursor"},
+				pgerror.ErrDuplicatedKey:       []any{fiber.StatusConflict, "profileRegistered"},
+				gorm.ErrRecordNotFound:         []any{fiber.StatusNotFound, "profileNotFound"},
 			},
 		}),
 	}
 
 	route.Use(middleware.MidAccess)
 
-	route.Get("", middlewareProfileFilterDTO, handler.getProfiles)
-	route.Post("", middlewareProfileDTO, handler.createProfile)
-	route.Put("/:"+utils.ParamID, middlewareIDIntDTO, middlewareProfileDTO, handler.updateProfile)
-	route.Delete("", middlewareIDsIntDTO, handler.deleteProfiles)
+	route.Get("", middleware.Permission(domain.PermProfilesRead), middlewareProfileFilterDTO, handler.getProfiles)
+	route.Get("/permissions", middleware.Permission(domain.PermProfilesRead), handler.getPermissions)
+	route.Post("", middleware.Permission(domain.PermProfilesWrite), middlewareProfileDTO, handler.createProfile)
+	route.Put("/:"+utils.ParamID, middleware.Permission(domain.PermProfilesWrite), middlewareIDIntDTO, middlewareProfileDTO, handler.updateProfile)
+	route.Delete("", middleware.Permission(domain.PermProfilesWrite), middlewareIDsIntDTO, handler.deleteProfiles)
 }
 
 // getProfiles godoc
@@ -55,10 +63,10 @@ func NewProfileHandler(route fiber.Router, service domain.ProfileService) {
 // @Tags         Profile
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        pgfilter			query	dto.ProfileFilter	false	"Profile Filter"
 // @Success      200  {array}   	dto.ItemsOutputDTO[dto.ProfileOutputDTO]
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /profile [get]
 // @Security	 Bearer
@@ -77,17 +85,37 @@ func (s *profileHandler) getProfiles(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// getPermissions godoc
+// @Summary      Get permissions
+// @Description  Get the permissions profiles may be granted
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Success      200  {array}   	dto.PermissionOutputDTO
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Router       /profile/permissions [get]
+// @Security	 Bearer
+func (s *profileHandler) getPermissions(c *fiber.Ctx) error {
+	permissions := s.service.GetPermissions(c.Context())
+	for i := range permissions {
+		permissions[i].Label = fiberi18n.MustLocalize(c, permissions[i].Label)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(permissions)
+}
+
 // createProfile godoc
 // @Summary      Insert profile
 // @Description  Insert profile
 // @Tags         Profile
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        profile			body	dto.ProfileInputDTO	true	"Profile model"
 // @Success      201  {object}  	dto.ProfileOutputDTO
 // @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      409  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /profile [post]
@@ -107,12 +135,12 @@ func (s *profileHandler) createProfile(c *fiber.Ctx) error {
 // @Tags         Profile
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header	bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header	string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        id					path    dto.IDFilter[uint]	true	"Profile ID"
 // @Param        profile			body	dto.ProfileInputDTO true	"Profile model"
 // @Success      200  {object}  	dto.ProfileOutputDTO
 // @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      404  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /profile/{id} [put]
@@ -133,10 +161,10 @@ func (s *profileHandler) updateProfile(c *fiber.Ctx) error {
 // @Tags         Profile
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header	bool					false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header	string					false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        ids				body	dto.IDsInputDTO[uint]   true	"Profiles ID"
 // @Success      204  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      404  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /profile [delete]
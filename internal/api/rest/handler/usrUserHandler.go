@@ -12,7 +12,9 @@ import (
 	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/passpolicy"
 	"github.com/raulaguila/go-api/pkg/pgerror"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
@@ -22,10 +24,22 @@ var middlewareUserDTO = datatransferobject.New(datatransferobject.Config{
 	Model:      &dto.UserInputDTO{},
 })
 
-var middlewarePasswordDTO = datatransferobject.New(datatransferobject.Config{
+var middlewareInvitationDTO = datatransferobject.New(datatransferobject.Config{
 	ContextKey: utils.LocalDTO,
 	OnLookup:   datatransferobject.Body,
-	Model:      &dto.PasswordInputDTO{},
+	Model:      &dto.InvitationInputDTO{},
+})
+
+var middlewarePasswordResetRequestDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.PasswordResetRequestDTO{},
+})
+
+var middlewarePasswordResetDTO = datatransferobject.New(datatransferobject.Config{
+	ContextKey: utils.LocalDTO,
+	OnLookup:   datatransferobject.Body,
+	Model:      &dto.PasswordResetInputDTO{},
 })
 
 type userHandler struct {
@@ -44,7 +58,21 @@ func NewUserHandler(route fiber.Router, service domain.UserService) {
 				utils.ErrInvalidID:            []any{fiber.StatusBadRequest, "invalidID"},
 				utils.ErrUserHasPass:          []any{fiber.StatusBadRequest, "hasPass"},
 				utils.ErrPasswordsDoNotMatch:  []any{fiber.StatusBadRequest, "passNotMatch"},
+				utils.ErrPasswordReused:       []any{fiber.StatusBadRequest, "passReused"},
+				passpolicy.ErrTooShort:        []any{fiber.StatusBadRequest, "passTooShort"},
+				passpolicy.ErrMissingUpper:    []any{fiber.StatusBadRequest, "passMissingUpper"},
+				passpolicy.ErrMissingLower:    []any{fiber.StatusBadRequest, "passMissingLower"},
+				passpolicy.ErrMissingDigit:    []any{fiber.StatusBadRequest, "passMissingDigit"},
+				passpolicy.ErrMissingSymbol:   []any{fiber.StatusBadRequest, "passMissingSymbol"},
+				passpolicy.ErrCommon:          []any{fiber.StatusBadRequest, "passCommon"},
+				passpolicy.ErrPersonalData:    []any{fiber.StatusBadRequest, "passPersonalData"},
+				utils.ErrInvalidToken:         []any{fiber.StatusBadRequest, "invalidResetToken"},
+				utils.ErrInvalidInvitation:    []any{fiber.StatusBadRequest, "invalidInvitation"},
 				pgerror.ErrUndefinedColumn:    []any{fiber.StatusBadRequest, "undefinedColumn"},
+				pgfilter.ErrInvalidSort:       []any{fiber.StatusBadRequest, "invalidSort"},
+				pgfilter.ErrInvalidFilter:     []any{fiber.StatusBadRequest, "invalidFilter"},
+				pgfilter.ErrInvalidCursor:     []any{fiber.StatusBadRequest, "invalidCursor"},
+				pgfilter.ErrInvalidSearchMode: []any{fiber.StatusBadRequest, "invalidSearchMode"},
 				pgerror.ErrDuplicatedKey:      []any{fiber.StatusConflict, "userRegistered"},
 				pgerror.ErrForeignKeyViolated: []any{fiber.StatusNotFound, "itemNotFound"},
 				gorm.ErrRecordNotFound:        []any{fiber.StatusNotFound, "userNotFound"},
@@ -52,15 +80,20 @@ func NewUserHandler(route fiber.Router, service domain.UserService) {
 		}),
 	}
 
-	route.Put("/pass", middlewarePasswordDTO, handler.setUserPassword)
+	route.Put("/invite", middlewareInvitationDTO, handler.acceptInvitation)
+	route.Post("/pass/reset", middlewarePasswordResetRequestDTO, handler.requestPasswordReset)
+	route.Put("/pass/reset", middlewarePasswordResetDTO, handler.confirmPasswordReset)
 
 	route.Use(middleware.MidAccess)
 
-	route.Delete("/pass", handler.resetUserPassword)
-	route.Get("", middlewareUserFilterDTO, handler.getUsers)
-	route.Post("", middlewareUserDTO, handler.createUser)
-	route.Put("/:"+utils.ParamID, middlewareIDIntDTO, middlewareUserDTO, handler.updateUser)
-	route.Delete("", middlewareIDsIntDTO, handler.deleteUser)
+	route.Delete("/pass", middleware.Permission(domain.PermUsersWrite), handler.resetUserPassword)
+	route.Get("", middleware.Permission(domain.PermUsersRead), middlewareUserFilterDTO, handler.getUsers)
+	route.Post("", middleware.Permission(domain.PermUsersWrite), middlewareUserDTO, handler.createUser)
+	route.Put("/:"+utils.ParamID, middleware.Permission(domain.PermUsersWrite), middlewareIDIntDTO, middlewareUserDTO, handler.updateUser)
+	route.Delete("", middleware.Permission(domain.PermUsersWrite), middlewareIDsIntDTO, handler.deleteUser)
+	route.Delete("/:"+utils.ParamID+"/2fa", middleware.Permission(domain.PermUsersWrite), middlewareIDIntDTO, handler.resetUserTwoFactor)
+	route.Delete("/:"+utils.ParamID+"/lock", middleware.Permission(domain.PermUsersWrite), middlewareIDIntDTO, handler.unlockUser)
+	route.Post("/:"+utils.ParamID+"/invite", middleware.Permission(domain.PermUsersWrite), middlewareIDIntDTO, handler.inviteUser)
 }
 
 // getUsers godoc
@@ -69,10 +102,10 @@ func NewUserHandler(route fiber.Router, service domain.UserService) {
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        pgfilter			query		dto.UserFilter		false	"Optional Filter"
 // @Success      200  {array}   	dto.ItemsOutputDTO[dto.UserOutputDTO]
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /user [get]
 // @Security	 Bearer
@@ -91,11 +124,11 @@ func (h *userHandler) getUsers(c *fiber.Ctx) error {
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        user				body		dto.UserInputDTO	true	"User model"
 // @Success      201  {object}  	dto.UserOutputDTO
 // @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      409  {object}  	HTTPResponse.Response
 // @Failure      500  {object} 		HTTPResponse.Response
 // @Router       /user [post]
@@ -116,12 +149,12 @@ func (h *userHandler) createUser(c *fiber.Ctx) error {
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        id					path		dto.IDFilter[uint]	true	"User ID"
 // @Param        user				body		dto.UserInputDTO	true	"User model"
 // @Success      200  {object}  	dto.UserOutputDTO
 // @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      404  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /user/{id} [put]
@@ -142,10 +175,10 @@ func (h *userHandler) updateUser(c *fiber.Ctx) error {
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        id					body		dto.IDsInputDTO[uint]	true	"User ID"
 // @Success      204  {object}  	nil
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      404  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /user [delete]
@@ -160,15 +193,15 @@ func (h *userHandler) deleteUser(c *fiber.Ctx) error {
 }
 
 // resetUser godoc
-// @Summary      Reset user password by ID
-// @Description  Reset user password by ID
+// @Summary      Reset user password by email
+// @Description  Log the user out of every session and mail a password reset token
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool				false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
 // @Param        email				query		string				true 	"User email"
 // @Success      200  {object}  	nil
+// @Failure      403  {object}  	HTTPResponse.Response
 // @Failure      404  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
 // @Router       /user/pass [delete]
@@ -186,34 +219,157 @@ func (h *userHandler) resetUserPassword(c *fiber.Ctx) error {
 	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passReset"), nil)
 }
 
-// passwordUser godoc
-// @Summary      Set user password by ID
-// @Description  Set user password by ID
+// acceptInvitation godoc
+// @Summary      Accept invitation
+// @Description  Set the first password of an invited user using the mailed invitation token
 // @Tags         User
 // @Accept       json
 // @Produce      json
-// @Param        X-Skip-Auth		header		bool					false	"Skip auth" enums(true,false) default(true)
 // @Param        Accept-Language	header		string					false	"Request language" enums(en-US,pt-BR) default(en-US)
-// @Param        email				query		string					true	"User email" Format(email)
-// @Param        password			body		dto.PasswordInputDTO	true	"Password model"
-// @Success      200  {object}  	nil
-// @Failure      404  {object}  	HTTPResponse.Response
+// @Param        invitation			body		dto.InvitationInputDTO	true	"Invitation model"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
 // @Failure      500  {object}  	HTTPResponse.Response
-// @Router       /user/pass [put]
-func (h *userHandler) setUserPassword(c *fiber.Ctx) error {
-	email, err := url.QueryUnescape(c.Query(utils.ParamMail, ""))
-	if err != nil {
+// @Router       /user/invite [put]
+func (h *userHandler) acceptInvitation(c *fiber.Ctx) error {
+	invitation := c.Locals(utils.LocalDTO).(*dto.InvitationInputDTO)
+	if invitation.Token == nil || *invitation.Token == "" {
+		return h.handlerError(c, utils.ErrInvalidInvitation)
+	}
+
+	if invitation.Password == nil || invitation.PasswordConfirm == nil || *invitation.Password != *invitation.PasswordConfirm {
+		return h.handlerError(c, utils.ErrPasswordsDoNotMatch)
+	}
+
+	if err := h.service.AcceptInvitation(c.Context(), invitation); err != nil {
 		return h.handlerError(c, err)
 	}
 
-	pass := c.Locals(utils.LocalDTO).(*dto.PasswordInputDTO)
-	if pass.Password == nil || pass.PasswordConfirm == nil || *pass.Password != *pass.PasswordConfirm {
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passSet"), nil)
+}
+
+// requestPasswordReset godoc
+// @Summary      Request password reset
+// @Description  Mail a single use password reset token if the email belongs to an active user
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        email				body		dto.PasswordResetRequestDTO	true	"Reset request model"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /user/pass/reset [post]
+func (h *userHandler) requestPasswordReset(c *fiber.Ctx) error {
+	request := c.Locals(utils.LocalDTO).(*dto.PasswordResetRequestDTO)
+	if request.Email == nil || *request.Email == "" {
+		return HTTPResponse.New(c, fiber.StatusBadRequest, fiberi18n.MustLocalize(c, "invalidData"), nil)
+	}
+
+	if err := h.service.RequestPasswordReset(c.Context(), *request.Email); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passResetRequested"), nil)
+}
+
+// confirmPasswordReset godoc
+// @Summary      Confirm password reset
+// @Description  Set a new password using a password reset token
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string						false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        reset				body		dto.PasswordResetInputDTO	true	"Reset model"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /user/pass/reset [put]
+func (h *userHandler) confirmPasswordReset(c *fiber.Ctx) error {
+	reset := c.Locals(utils.LocalDTO).(*dto.PasswordResetInputDTO)
+	if reset.Token == nil || *reset.Token == "" {
+		return h.handlerError(c, utils.ErrInvalidToken)
+	}
+
+	if reset.Password == nil || reset.PasswordConfirm == nil || *reset.Password != *reset.PasswordConfirm {
 		return h.handlerError(c, utils.ErrPasswordsDoNotMatch)
 	}
 
-	if err := h.service.SetUserPassword(c.Context(), email, pass); err != nil {
+	if err := h.service.ConfirmPasswordReset(c.Context(), reset); err != nil {
 		return h.handlerError(c, err)
 	}
 
 	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "passSet"), nil)
 }
+
+// resetUserTwoFactor godoc
+// @Summary      Reset user two-factor authentication by ID
+// @Description  Disable two-factor authentication of a user that lost the authenticator
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		int					true	"User ID"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /user/{id}/2fa [delete]
+// @Security	 Bearer
+func (h *userHandler) resetUserTwoFactor(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[uint])
+	if err := h.service.ResetUserTwoFactor(c.Context(), id.ID); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "twoFactorDisabled"), nil)
+}
+
+// unlockUser godoc
+// @Summary      Unlock user by ID
+// @Description  Clear the failed logins of a user, lifting its lock
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		int					true	"User ID"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /user/{id}/lock [delete]
+// @Security	 Bearer
+func (h *userHandler) unlockUser(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[uint])
+	if err := h.service.UnlockUser(c.Context(), id.ID); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "userUnlocked"), nil)
+}
+
+// inviteUser godoc
+// @Summary      Resend invitation by ID
+// @Description  Mail a new invitation to a user without password, invalidating the previous ones
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Param        Accept-Language	header		string				false	"Request language" enums(en-US,pt-BR) default(en-US)
+// @Param        id					path		int					true	"User ID"
+// @Success      200  {object}  	HTTPResponse.Response
+// @Failure      400  {object}  	HTTPResponse.Response
+// @Failure      403  {object}  	HTTPResponse.Response
+// @Failure      404  {object}  	HTTPResponse.Response
+// @Failure      500  {object}  	HTTPResponse.Response
+// @Router       /user/{id}/invite [post]
+// @Security	 Bearer
+func (h *userHandler) inviteUser(c *fiber.Ctx) error {
+	id := c.Locals(utils.LocalID).(*dto.IDFilter[uint])
+	if err := h.service.InviteUser(c.Context(), id.ID); err != nil {
+		return h.handlerError(c, err)
+	}
+
+	return HTTPResponse.New(c, fiber.StatusOK, fiberi18n.MustLocalize(c, "invitationSent"), nil)
+}
@@ -1,18 +1,19 @@
 package middleware
 
 import (
-	"crypto/rsa"
 	"errors"
 	"log"
-	"os"
+	"strings"
+	"time"
 
 	"github.com/gofiber/contrib/fiberi18n/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/keyauth"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 
 	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
 	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/packhub"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
@@ -21,53 +22,208 @@ var (
 	MidRefresh fiber.Handler
 )
 
-func Auth(parsedKey *rsa.PrivateKey, repo domain.UserRepository) fiber.Handler {
-	return keyauth.New(keyauth.Config{
-		KeyLookup:  "header:" + fiber.HeaderAuthorization,
-		AuthScheme: "Bearer",
-		ContextKey: "token",
-		Next: func(c *fiber.Ctx) bool {
-			// Filter request to skip middleware
-			// true to skip, false to not skip
-			c.Locals(utils.LocalUser, new(domain.User))
-			return os.Getenv("API_ACCEPT_SKIP_AUTH") == "1" && c.Get("X-Skip-Auth", "false") == "true"
-		},
-		SuccessHandler: func(c *fiber.Ctx) error {
-			return c.Next()
-		},
+// HeaderAPIKey carries an API key, which may also be sent as a Bearer token.
+const HeaderAPIKey string = "X-API-Key"
+
+// Auth authenticates the request with a JWT of the given type verified by tokens, sent as a Bearer token
+// or, without Authorization header, in the type's cookie. When apiKeyRepo isn't nil, API keys are accepted
+// as well, either on the X-API-Key header or as Bearer tokens. The user's effective permissions are resolved
+// from profileRepo once, before the request goes on. Requests changing state under impersonation are
+// recorded in auditRepo once answered.
+func Auth(tokens *domain.TokenIssuer, tokenType string, repo domain.UserRepository, sessionRepo domain.SessionRepository, apiKeyRepo domain.APIKeyRepository, profileRepo domain.ProfileRepository, auditRepo domain.AuditRepository) fiber.Handler {
+	validateAPIKey := func(c *fiber.Ctx, key string) error {
+		prefix, ok := domain.ParseAPIKeyPrefix(key)
+		if !ok {
+			return errors.New(fiberi18n.MustLocalize(c, "invalidAPIKey"))
+		}
+
+		apiKey := &domain.APIKey{Prefix: prefix}
+		if err := apiKeyRepo.GetAPIKey(c.Context(), apiKey); err != nil || !apiKey.Matches(key) || !apiKey.Active() {
+			return errors.New(fiberi18n.MustLocalize(c, "invalidAPIKey"))
+		}
+
+		user := &domain.User{AuthID: apiKey.AuthID}
+		if err := repo.GetUser(c.Context(), user); err != nil {
+			log.Println(err)
+			return errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
+		}
+
+		if !user.Auth.Status {
+			return errors.New(fiberi18n.MustLocalize(c, "disabledUser"))
+		}
+
+		if err := resolvePermissions(c.Context(), profileRepo, user); err != nil {
+			log.Println(err)
+			return errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
+		}
+
+		if apiKey.NeedsTouch() {
+			apiKey.LastUsedAt = packhub.Pointer(time.Now())
+			if err := apiKeyRepo.TouchAPIKey(c.Context(), apiKey); err != nil {
+				log.Println(err)
+			}
+		}
+
+		c.Locals(utils.LocalUser, user)
+		c.Locals(utils.LocalAPIKey, apiKey)
+		return nil
+	}
+
+	config := keyauth.Config{
+		ContextKey:     "token",
+		SuccessHandler: auditImpersonation(auditRepo),
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			return HTTPResponse.New(c, fiber.StatusUnauthorized, err.Error(), nil)
 		},
 		Validator: func(c *fiber.Ctx, key string) (bool, error) {
-			parsedToken, err := jwt.Parse(key, func(token *jwt.Token) (any, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-					return nil, jwt.ErrTokenSignatureInvalid
+			if apiKeyRepo != nil && strings.HasPrefix(key, domain.APIKeyScheme) {
+				if err := validateAPIKey(c, key); err != nil {
+					return false, err
 				}
+				return true, nil
+			}
 
-				return parsedKey.Public(), nil
-			})
+			claims, err := tokens.Parse(key, tokenType)
 			if err != nil {
 				log.Println(err)
 				return false, errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
 			}
 
-			claims, ok := parsedToken.Claims.(jwt.MapClaims)
-			if !ok || !parsedToken.Valid {
-				return false, errors.New("invalid jwt token")
-			}
-
-			user, err := repo.GetUserByToken(c.Context(), claims["token"].(string))
+			user, err := repo.GetUserByToken(c.Context(), claims.Token)
 			if err != nil {
 				log.Println(err)
 				return false, errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
 			}
 
+			if user.Subject() != claims.Subject {
+				return false, errors.New("invalid jwt token")
+			}
+
 			if !user.Auth.Status {
 				return false, errors.New(fiberi18n.MustLocalize(c, "disabledUser"))
 			}
 
+			session := &domain.Session{}
+			if session.ID, err = uuid.Parse(claims.SessionID); err != nil {
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			if err := sessionRepo.GetSession(c.Context(), session); err != nil || session.AuthID != user.AuthID || !session.Active() {
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			// Impersonation sessions only accept tokens naming their actor, who must still be allowed to impersonate.
+			if session.Impersonated() != (claims.Actor != nil) {
+				return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+			}
+
+			if claims.Actor != nil {
+				actor := &domain.User{AuthID: *session.ActorAuthID}
+				if err := repo.GetUser(c.Context(), actor); err != nil || actor.Subject() != claims.Actor.Subject || !actor.Auth.Status {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+
+				if err := resolvePermissions(c.Context(), profileRepo, actor); err != nil || !actor.HasPermission(domain.PermUsersImpersonate) {
+					return false, errors.New(fiberi18n.MustLocalize(c, "invalidSession"))
+				}
+
+				log.Printf("user %s acting as user %s: %s %s", actor.Subject(), user.Subject(), c.Method(), c.OriginalURL())
+				c.Locals(utils.LocalActor, actor)
+			}
+
+			if err := resolvePermissions(c.Context(), profileRepo, user); err != nil {
+				log.Println(err)
+				return false, errors.New(fiberi18n.MustLocalize(c, "errGeneric"))
+			}
+
+			if session.NeedsTouch() {
+				session.IP, session.UserAgent, session.LastUsedAt = c.IP(), domain.UserAgent(c.Get(fiber.HeaderUserAgent)), packhub.Pointer(time.Now())
+				if err := sessionRepo.TouchSession(c.Context(), session); err != nil {
+					log.Println(err)
+				}
+			}
+
 			c.Locals(utils.LocalUser, user)
+			c.Locals(utils.LocalSession, session)
+			c.Locals(utils.LocalClaims, claims)
 			return true, nil
 		},
-	})
+	}
+
+	headerConfig := config
+	headerConfig.KeyLookup, headerConfig.AuthScheme = "header:"+fiber.HeaderAuthorization, "Bearer"
+	headerHandler := keyauth.New(headerConfig)
+
+	cookieName := CookieAccessToken
+	if tokenType == domain.TokenTypeRefresh {
+		cookieName = CookieRefreshToken
+	}
+
+	cookieConfig := config
+	cookieConfig.KeyLookup = "cookie:" + cookieName
+	cookieHandler := keyauth.New(cookieConfig)
+
+	return func(c *fiber.Ctx) error {
+		if key := c.Get(HeaderAPIKey); apiKeyRepo != nil && key != "" {
+			if err := validateAPIKey(c, key); err != nil {
+				return HTTPResponse.New(c, fiber.StatusUnauthorized, err.Error(), nil)
+			}
+			return c.Next()
+		}
+
+		if usesCookie(c, cookieName) {
+			if !validCSRF(c) {
+				return HTTPResponse.New(c, fiber.StatusForbidden, fiberi18n.MustLocalize(c, "invalidCSRFToken"), nil)
+			}
+
+			c.Locals(utils.LocalCookieAuth, true)
+			return cookieHandler(c)
+		}
+
+		return headerHandler(c)
+	}
+}
+
+// auditImpersonation goes on with the request, recording it in auditRepo once answered when it changes
+// state under impersonation. The client's values are cut to fit, so no request escapes the audit.
+func auditImpersonation(auditRepo domain.AuditRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, ok := c.Locals(utils.LocalActor).(*domain.User)
+		if !ok || safeMethod(c.Method()) {
+			return c.Next()
+		}
+
+		err := c.Next()
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		user, session := c.Locals(utils.LocalUser).(*domain.User), c.Locals(utils.LocalSession).(*domain.Session)
+		if auditErr := auditRepo.CreateAuditEvent(c.Context(), &domain.AuditEvent{
+			Action:      domain.AuditActionImpersonateRequest,
+			ActorAuthID: actor.AuthID,
+			AuthID:      &user.AuthID,
+			SessionID:   &session.ID,
+			IP:          c.IP(),
+			UserAgent:   domain.UserAgent(c.Get(fiber.HeaderUserAgent)),
+			Method:      c.Method(),
+			Path:        domain.AuditPath(c.Path()),
+			Status:      status,
+		}); auditErr != nil {
+			log.Println(auditErr)
+		}
+
+		return err
+	}
+}
+
+// RequireSession rejects requests authenticated with an API key or an impersonation token, keeping
+// credential management to the user's own interactive logins. It must be placed after MidAccess.
+func RequireSession(c *fiber.Ctx) error {
+	if session, ok := c.Locals(utils.LocalSession).(*domain.Session); ok && !session.Impersonated() {
+		return c.Next()
+	}
+
+	return HTTPResponse.New(c, fiber.StatusForbidden, fiberi18n.MustLocalize(c, "forbidden"), nil)
 }
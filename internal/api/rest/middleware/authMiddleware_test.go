@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// auditRepository keeps the events, failing like the database on values too long for their columns.
+type auditRepository struct {
+	events []*domain.AuditEvent
+}
+
+func (s *auditRepository) CreateAuditEvent(_ context.Context, input *domain.AuditEvent) error {
+	if utf8.RuneCountInString(input.UserAgent) > domain.UserAgentLength || utf8.RuneCountInString(input.Path) > domain.AuditPathLength {
+		return errors.New("value too long for type character varying(255)")
+	}
+
+	s.events = append(s.events, input)
+	return nil
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	longPath := "/" + strings.Repeat("p", 300)
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		userAgent string
+		actor     bool
+		audited   bool
+	}{
+		{name: "unsafe method", method: fiber.MethodPost, path: "/test", userAgent: "Mozilla/5.0", actor: true, audited: true},
+		{name: "oversized user agent", method: fiber.MethodPut, path: "/test", userAgent: strings.Repeat("a", 300), actor: true, audited: true},
+		{name: "oversized path", method: fiber.MethodDelete, path: longPath, userAgent: "Mozilla/5.0", actor: true, audited: true},
+		{name: "safe method", method: fiber.MethodGet, path: "/test", userAgent: "Mozilla/5.0", actor: true},
+		{name: "without actor", method: fiber.MethodPost, path: "/test", userAgent: "Mozilla/5.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &auditRepository{}
+			session := &domain.Session{BaseUUID: domain.BaseUUID{ID: uuid.New()}}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(utils.LocalUser, &domain.User{AuthID: 2})
+				c.Locals(utils.LocalSession, session)
+				if tt.actor {
+					c.Locals(utils.LocalActor, &domain.User{AuthID: 1})
+				}
+				return c.Next()
+			}, auditImpersonation(repo))
+			app.All("/*", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusAccepted)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(fiber.HeaderUserAgent, tt.userAgent)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+			if !tt.audited {
+				require.Empty(t, repo.events)
+				return
+			}
+
+			require.Len(t, repo.events, 1)
+			event := repo.events[0]
+			require.Equal(t, domain.AuditActionImpersonateRequest, event.Action)
+			require.Equal(t, uint(1), event.ActorAuthID)
+			require.Equal(t, uint(2), *event.AuthID)
+			require.Equal(t, session.ID, *event.SessionID)
+			require.Equal(t, tt.method, event.Method)
+			require.Equal(t, fiber.StatusAccepted, event.Status)
+			require.True(t, strings.HasPrefix(tt.userAgent, event.UserAgent))
+			require.True(t, strings.HasPrefix(tt.path, event.Path))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/pkg/secret"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// Browser clients may keep their tokens in HttpOnly cookies instead of the Authorization header. Those
+// requests must then repeat the readable CSRF cookie in the X-CSRF-Token header when changing state,
+// which a cross-site request can't do.
+const (
+	CookieAccessToken  string = "access_token"
+	CookieRefreshToken string = "refresh_token"
+	CookieCSRFToken    string = "csrf_token"
+
+	HeaderCSRFToken string = "X-CSRF-Token"
+)
+
+// CookieAuth reports whether the request was authenticated with a token cookie.
+func CookieAuth(c *fiber.Ctx) bool {
+	fromCookie, _ := c.Locals(utils.LocalCookieAuth).(bool)
+	return fromCookie
+}
+
+// usesCookie reports whether the request is to be authenticated with the named token cookie, which
+// happens when it has no Authorization header.
+func usesCookie(c *fiber.Ctx, name string) bool {
+	return c.Get(fiber.HeaderAuthorization) == "" && c.Cookies(name) != ""
+}
+
+// safeMethod reports whether the method only reads state.
+func safeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// validCSRF applies the double-submit check to requests authenticated with cookies: unless the method is
+// safe, the CSRF header has to match the CSRF cookie.
+func validCSRF(c *fiber.Ctx) bool {
+	if safeMethod(c.Method()) {
+		return true
+	}
+
+	token := c.Cookies(CookieCSRFToken)
+	return token != "" && secret.Equal(token, c.Get(HeaderCSRFToken))
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+func TestCookieCSRF(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		bearer       bool
+		cookie       string
+		header       string
+		expectedCode int
+	}{
+		{name: "safe method without header", method: fiber.MethodGet, cookie: "csrf", expectedCode: fiber.StatusOK},
+		{name: "head without header", method: fiber.MethodHead, cookie: "csrf", expectedCode: fiber.StatusOK},
+		{name: "options without header", method: fiber.MethodOptions, cookie: "csrf", expectedCode: fiber.StatusOK},
+		{name: "unsafe method with matching header", method: fiber.MethodPost, cookie: "csrf", header: "csrf", expectedCode: fiber.StatusOK},
+		{name: "unsafe method without header", method: fiber.MethodPost, cookie: "csrf", expectedCode: fiber.StatusForbidden},
+		{name: "unsafe method with mismatched header", method: fiber.MethodDelete, cookie: "csrf", header: "other", expectedCode: fiber.StatusForbidden},
+		{name: "unsafe method without csrf cookie", method: fiber.MethodPut, header: "csrf", expectedCode: fiber.StatusForbidden},
+		{name: "unsafe method without csrf cookie nor header", method: fiber.MethodPut, expectedCode: fiber.StatusForbidden},
+		{name: "bearer takes precedence", method: fiber.MethodPost, bearer: true, cookie: "csrf", expectedCode: fiber.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.All("/test", func(c *fiber.Ctx) error {
+				if !usesCookie(c, CookieAccessToken) {
+					require.False(t, CookieAuth(c))
+					return c.SendStatus(fiber.StatusNoContent)
+				}
+				if !validCSRF(c) {
+					return c.SendStatus(fiber.StatusForbidden)
+				}
+
+				c.Locals(utils.LocalCookieAuth, true)
+				require.True(t, CookieAuth(c))
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			req.Header.Set(fiber.HeaderCookie, CookieAccessToken+"=token")
+			if tt.cookie != "" {
+				req.Header.Add(fiber.HeaderCookie, CookieCSRFToken+"="+tt.cookie)
+			}
+			if tt.header != "" {
+				req.Header.Set(HeaderCSRFToken, tt.header)
+			}
+			if tt.bearer {
+				req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err, fmt.Sprintf("Error on test '%v'", tt.name))
+			require.Equal(t, tt.expectedCode, resp.StatusCode, fmt.Sprintf("Wrong status code on test '%v'", tt.name))
+		})
+	}
+}
+
+func TestUsesCookie(t *testing.T) {
+	app := fiber.New()
+	app.Get("/test", func(c *fiber.Ctx) error {
+		if usesCookie(c, CookieRefreshToken) {
+			return c.SendStatus(fiber.StatusOK)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/test", nil)
+	req.Header.Set(fiber.HeaderCookie, CookieAccessToken+"=token")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNoContent, resp.StatusCode, "only the named cookie is used")
+
+	req = httptest.NewRequest(fiber.MethodGet, "/test", nil)
+	req.Header.Set(fiber.HeaderCookie, CookieRefreshToken+"=token")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
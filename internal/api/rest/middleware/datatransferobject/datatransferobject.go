@@ -2,11 +2,18 @@ package datatransferobject
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// QueryBinder is implemented by models reading query parameters the parser can't map to their fields.
+// BindQuery receives every query parameter once the model is parsed.
+type QueryBinder interface {
+	BindQuery(query url.Values) error
+}
+
 func New(config ...Config) fiber.Handler {
 	cfg := configDefault(config...)
 
@@ -15,7 +22,18 @@ func New(config ...Config) fiber.Handler {
 		case Body:
 			return obj, c.BodyParser(obj)
 		case Query:
-			return obj, c.QueryParser(obj)
+			if err := c.QueryParser(obj); err != nil {
+				return obj, err
+			}
+
+			if binder, ok := obj.(QueryBinder); ok {
+				query := url.Values{}
+				c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+					query.Add(string(key), string(value))
+				})
+				return obj, binder.BindQuery(query)
+			}
+			return obj, nil
 		case Params:
 			return obj, c.ParamsParser(obj)
 		default:
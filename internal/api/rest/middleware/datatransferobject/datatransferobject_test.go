@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -106,3 +107,34 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+type TestQueryModel struct {
+	Name    string `query:"name"`
+	Filters map[string]string
+}
+
+func (s *TestQueryModel) BindQuery(query url.Values) error {
+	s.Filters = map[string]string{}
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") {
+			s.Filters[key] = query.Get(key)
+		}
+	}
+	return nil
+}
+
+func TestNewQueryBinder(t *testing.T) {
+	app := fiber.New()
+	app.Use(New(Config{OnLookup: Query, Model: &TestQueryModel{}}))
+	app.Get("/test", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(c.Locals("localDTO"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/test?name=John&filter[name][ilike]=jo&filter[id][in]=1,2", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Name":"John","Filters":{"filter[name][ilike]":"jo","filter[id][in]":"1,2"}}`, string(body))
+}
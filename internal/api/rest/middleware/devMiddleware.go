@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"log"
+	"net/netip"
+
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// DevIdentity is the user requests without credentials run as during development, with the profile
+// ProfileID instead of its own when not zero. Only requests from Networks get it.
+type DevIdentity struct {
+	UserID    uint
+	ProfileID uint
+	Networks  []netip.Prefix
+}
+
+// accepts reports whether the request carries no credentials at all and comes from an allowed network.
+func (s *DevIdentity) accepts(c *fiber.Ctx) bool {
+	if c.Get(fiber.HeaderAuthorization) != "" || c.Get(HeaderAPIKey) != "" || c.Cookies(CookieAccessToken) != "" {
+		return false
+	}
+
+	ip, err := netip.ParseAddr(c.IP())
+	if err != nil {
+		return false
+	}
+
+	for _, network := range s.Networks {
+		if network.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithDevIdentity authenticates the requests identity accepts as its user, leaving the others to next.
+// The user gets no session, so it can't manage credentials. A nil identity returns next unchanged.
+func WithDevIdentity(identity *DevIdentity, repo domain.UserRepository, profileRepo domain.ProfileRepository, next fiber.Handler) fiber.Handler {
+	if identity == nil {
+		return next
+	}
+
+	return func(c *fiber.Ctx) error {
+		if !identity.accepts(c) {
+			return next(c)
+		}
+
+		user := &domain.User{BaseInt: domain.BaseInt{ID: identity.UserID}}
+		if err := repo.GetUser(c.Context(), user); err != nil {
+			log.Println(err)
+			return HTTPResponse.New(c, fiber.StatusUnauthorized, fiberi18n.MustLocalize(c, "errGeneric"), nil)
+		}
+
+		// Only the loaded profile is replaced, so saving the user keeps its real one.
+		if identity.ProfileID != 0 {
+			profile := &domain.Profile{BaseInt: domain.BaseInt{ID: identity.ProfileID}}
+			if err := profileRepo.GetProfile(c.Context(), profile); err != nil {
+				log.Println(err)
+				return HTTPResponse.New(c, fiber.StatusUnauthorized, fiberi18n.MustLocalize(c, "errGeneric"), nil)
+			}
+			user.Auth.Profile = profile
+		}
+
+		if err := resolvePermissions(c.Context(), profileRepo, user); err != nil {
+			log.Println(err)
+			return HTTPResponse.New(c, fiber.StatusUnauthorized, fiberi18n.MustLocalize(c, "errGeneric"), nil)
+		}
+
+		log.Printf("WARNING: development identity: %s %s from %s runs as user %s with profile %s", c.Method(), c.OriginalURL(), c.IP(), user.Subject(), user.Auth.Profile.Name)
+		c.Locals(utils.LocalUser, user)
+		return c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/contrib/fiberi18n/v2"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/raulaguila/go-api/internal/pkg/HTTPResponse"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// Permission only lets the request through when the authenticated user's profile grants the
+// given permission, and so does the API key used, if any. It must be placed after MidAccess,
+// which stores the user in the context.
+func Permission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals(utils.LocalUser).(*domain.User)
+		if ok {
+			if apiKey, isKey := c.Locals(utils.LocalAPIKey).(*domain.APIKey); isKey {
+				ok = apiKey.HasPermission(user, permission)
+			} else {
+				ok = user.HasPermission(permission)
+			}
+		}
+
+		if ok {
+			return c.Next()
+		}
+
+		return HTTPResponse.New(c, fiber.StatusForbidden, fiberi18n.MustLocalize(c, "forbidden"), nil)
+	}
+}
+
+// resolvePermissions resolves the effective permissions of the user's profile, so Permission checks
+// include the inherited ones without querying the hierarchy again.
+func resolvePermissions(ctx context.Context, profileRepo domain.ProfileRepository, user *domain.User) error {
+	if user.Auth == nil || user.Auth.Profile == nil {
+		return nil
+	}
+
+	ancestors, err := profileRepo.GetProfileAncestors(ctx, user.Auth.Profile.ID)
+	if err != nil {
+		return err
+	}
+
+	user.Auth.Profile.Inherit(*ancestors)
+	return nil
+}
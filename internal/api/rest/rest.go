@@ -2,6 +2,7 @@ package rest
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
 	"time"
@@ -25,37 +26,78 @@ import (
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/repository"
 	"github.com/raulaguila/go-api/internal/pkg/service"
+	"github.com/raulaguila/go-api/pkg/mailer"
 	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/utils"
 )
 
 var (
-	profileRepository domain.ProfileRepository
-	userRepository    domain.UserRepository
+	accessTokens     *domain.TokenIssuer
+	refreshTokens    *domain.TokenIssuer
+	twoFactorTokens  *domain.TokenIssuer
+	invitationTokens *domain.TokenIssuer
+	oidcStateTokens  *domain.TokenIssuer
+
+	profileRepository       domain.ProfileRepository
+	userRepository          domain.UserRepository
+	sessionRepository       domain.SessionRepository
+	passwordResetRepository domain.PasswordResetRepository
+	apiKeyRepository        domain.APIKeyRepository
+	identityRepository      domain.IdentityRepository
+	auditRepository         domain.AuditRepository
 
 	authService    domain.AuthService
 	profileService domain.ProfileService
 	userService    domain.UserService
+	apiKeyService  domain.APIKeyService
 )
 
+func initTokenIssuers() {
+	accessTokens = &domain.TokenIssuer{Keys: configs.AccessKeys, Issuer: configs.TokenIssuer, Audience: configs.TokenAudience}
+	refreshTokens = &domain.TokenIssuer{Keys: configs.RefreshKeys, Issuer: configs.TokenIssuer, Audience: configs.TokenAudience}
+	twoFactorTokens = accessTokens.Purpose(domain.TwoFactorChallengeType)
+	invitationTokens = accessTokens.Purpose(domain.TokenTypeInvitation)
+	oidcStateTokens = accessTokens.Purpose(domain.TokenTypeOIDCState)
+}
+
+// devIdentity returns the configured development identity, if any.
+func devIdentity() *middleware.DevIdentity {
+	if configs.DevUserID == 0 {
+		return nil
+	}
+
+	log.Printf("WARNING: development identity enabled, requests without credentials from %v run as user %d", configs.DevNetworks, configs.DevUserID)
+	return &middleware.DevIdentity{UserID: configs.DevUserID, ProfileID: configs.DevProfileID, Networks: configs.DevNetworks}
+}
+
 func initRepositories(postgresDB *gorm.DB, minioClient *minio.Client) {
 	profileRepository = repository.NewProfileRepository(postgresDB)
 	userRepository = repository.NewUserRepository(postgresDB)
+	sessionRepository = repository.NewSessionRepository(postgresDB)
+	passwordResetRepository = repository.NewPasswordResetRepository(postgresDB)
+	apiKeyRepository = repository.NewAPIKeyRepository(postgresDB)
+	identityRepository = repository.NewIdentityRepository(postgresDB)
+	auditRepository = repository.NewAuditRepository(postgresDB)
 }
 
-func initServices() {
+func initServices(mailSender mailer.Sender) {
 	profileService = service.NewProfileService(profileRepository)
-	authService = service.NewAuthService(userRepository)
-	userService = service.NewUserService(userRepository)
+	authService = service.NewAuthService(userRepository, sessionRepository, identityRepository, profileRepository, auditRepository, accessTokens, refreshTokens, twoFactorTokens, oidcStateTokens)
+	userService = service.NewUserService(userRepository, sessionRepository, passwordResetRepository, invitationTokens, mailSender)
+	apiKeyService = service.NewAPIKeyService(apiKeyRepository)
 }
 
 func initHandlers(app *fiber.App) {
 	// Initialize access middlewares
-	middleware.MidAccess = middleware.Auth(configs.AccessPrivateKey, userRepository)
-	middleware.MidRefresh = middleware.Auth(configs.RefreshPrivateKey, userRepository)
+	middleware.MidAccess = middleware.WithDevIdentity(devIdentity(), userRepository, profileRepository,
+		middleware.Auth(accessTokens, domain.TokenTypeAccess, userRepository, sessionRepository, apiKeyRepository, profileRepository, auditRepository),
+	)
+	middleware.MidRefresh = middleware.Auth(refreshTokens, domain.TokenTypeRefresh, userRepository, sessionRepository, nil, profileRepository, auditRepository)
 
 	// Prepare endpoints for the API.
-	handler.NewMiscHandler(app.Group(""))
+	handler.NewMiscHandler(app.Group(""), configs.AccessKeys)
 	handler.NewAuthHandler(app.Group("/auth"), authService)
+	handler.NewAPIKeyHandler(app.Group("/auth/keys"), apiKeyService)
 
 	handler.NewProfileHandler(app.Group("/profile"), profileService)
 
@@ -67,7 +109,7 @@ func initHandlers(app *fiber.App) {
 	})
 }
 
-func start(app *fiber.App, postgresDB *gorm.DB, minioClient *minio.Client) {
+func start(app *fiber.App, postgresDB *gorm.DB, minioClient *minio.Client, mailSender mailer.Sender) {
 	if strings.ToLower(os.Getenv("API_SWAGGO")) == "1" {
 		docs.SwaggerInfo.Version = os.Getenv("SYS_VERSION")
 
@@ -79,14 +121,15 @@ func start(app *fiber.App, postgresDB *gorm.DB, minioClient *minio.Client) {
 		}))
 	}
 
+	initTokenIssuers()
 	initRepositories(postgresDB, minioClient)
-	initServices()
+	initServices(mailSender)
 	initHandlers(app)
 
 	packhub.PanicIfErr(app.Listen(":" + os.Getenv("API_PORT")))
 }
 
-func New(postgresDB *gorm.DB, minioClient *minio.Client) {
+func New(postgresDB *gorm.DB, minioClient *minio.Client, mailSender mailer.Sender) {
 	app := fiber.New(fiber.Config{
 		EnablePrintRoutes:     false,
 		Prefork:               os.Getenv("API_ENABLE_PREFORK") == "1",
@@ -121,8 +164,14 @@ func New(postgresDB *gorm.DB, minioClient *minio.Client) {
 					}
 					return output.WriteString("")
 				},
+				"xact": func(output logger.Buffer, c *fiber.Ctx, _ *logger.Data, _ string) (int, error) {
+					if actor, ok := c.Locals(utils.LocalActor).(*domain.User); ok {
+						return output.WriteString(fmt.Sprintf(" act:%s", actor.Subject()))
+					}
+					return output.WriteString("")
+				},
 			},
-			Format:     "[FIBER:${magenta}${xid}${reset}] ${time} | ${status} | ${latency} | ${xip} | ${method} ${fullPath} ${yellow}\"${reqHeader:Accept-Language}${xauth}\"${reset} ${magenta}${error}${reset}\n",
+			Format:     "[FIBER:${magenta}${xid}${reset}] ${time} | ${status} | ${latency} | ${xip} | ${method} ${fullPath} ${yellow}\"${reqHeader:Accept-Language}${xauth}\"${xact}${reset} ${magenta}${error}${reset}\n",
 			TimeFormat: "2006-01-02 15:04:05",
 			TimeZone:   time.Local.String(),
 		}))
@@ -155,5 +204,5 @@ func New(postgresDB *gorm.DB, minioClient *minio.Client) {
 		}),
 	)
 
-	start(app, postgresDB, minioClient)
+	start(app, postgresDB, minioClient, mailSender)
 }
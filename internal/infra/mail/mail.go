@@ -0,0 +1,16 @@
+package mail
+
+import (
+	"os"
+
+	"github.com/raulaguila/go-api/pkg/mailer"
+)
+
+func NewMailSender() mailer.Sender {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "smtp":
+		return mailer.NewSMTPSender(os.Getenv("MAIL_HOST"), os.Getenv("MAIL_PORT"), os.Getenv("MAIL_USER"), os.Getenv("MAIL_PASS"), os.Getenv("MAIL_FROM"))
+	default:
+		return mailer.NewFileSender(os.Getenv("MAIL_DIR"), os.Getenv("MAIL_FROM"))
+	}
+}
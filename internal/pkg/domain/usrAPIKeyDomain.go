@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/secret"
+)
+
+const (
+	APIKeyTableName string = "usr_api_key"
+
+	// APIKeyScheme starts every API key, telling them apart from JWTs sent as Bearer tokens.
+	APIKeyScheme string = "gak_"
+)
+
+type (
+	// APIKey is a long-lived credential of a user. The key is only shown once, at creation: the prefix
+	// identifies it and only its hash is stored. An empty Permissions grants everything the user's profile does.
+	APIKey struct {
+		BaseInt
+		AuthID      uint           `gorm:"column:auth_id;type:bigint;not null;index;"`
+		Name        string         `gorm:"column:name;type:varchar(100);not null;"`
+		Prefix      string         `gorm:"column:prefix;type:varchar(16);unique;not null;"`
+		KeyHash     string         `gorm:"column:key_hash;type:varchar(64);not null;"`
+		Permissions pq.StringArray `gorm:"column:permissions;type:text[];not null;"`
+		ExpiresAt   *time.Time     `gorm:"column:expires_at;type:timestamptz;"`
+		RevokedAt   *time.Time     `gorm:"column:revoked_at;type:timestamptz;"`
+		LastUsedAt  *time.Time     `gorm:"column:last_used_at;type:timestamptz;"`
+	}
+
+	APIKeyRepository interface {
+		GetAPIKey(context.Context, *APIKey) error
+		GetAPIKeys(context.Context, uint) (*[]APIKey, error)
+		CreateAPIKey(context.Context, *APIKey) error
+		TouchAPIKey(context.Context, *APIKey) error
+		RevokeAPIKey(context.Context, *APIKey) error
+	}
+
+	APIKeyService interface {
+		GetAPIKeys(context.Context, *User) ([]dto.APIKeyOutputDTO, error)
+		CreateAPIKey(context.Context, *User, *dto.APIKeyInputDTO) (*dto.APIKeyOutputDTO, error)
+		RevokeAPIKey(context.Context, *User, uint) error
+	}
+)
+
+func (s *APIKey) TableName() string { return APIKeyTableName }
+
+// GenerateKey sets a new random prefix and key hash, returning the key to be handed to the user.
+func (s *APIKey) GenerateKey() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	token, err := secret.NewToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.Prefix = hex.EncodeToString(buf)
+	key := APIKeyScheme + s.Prefix + "_" + token
+	s.KeyHash = secret.Hash(key)
+	return key, nil
+}
+
+// ParseAPIKeyPrefix returns the prefix of a key, or false when key isn't shaped like an API key.
+func ParseAPIKeyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyScheme)
+	if !ok {
+		return "", false
+	}
+
+	prefix, token, ok := strings.Cut(rest, "_")
+	return prefix, ok && prefix != "" && token != ""
+}
+
+func (s *APIKey) Matches(key string) bool {
+	return secret.Equal(s.KeyHash, secret.Hash(key))
+}
+
+func (s *APIKey) Active() bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(time.Now()))
+}
+
+func (s *APIKey) NeedsTouch() bool {
+	return s.LastUsedAt == nil || time.Since(*s.LastUsedAt) >= SessionTouchInterval
+}
+
+// HasPermission reports whether a request authenticated with the key may use the permission:
+// the user's profile must grant it and, when the key is restricted, so must one of the key's permissions.
+func (s *APIKey) HasPermission(user *User, permission string) bool {
+	if !user.HasPermission(permission) {
+		return false
+	}
+
+	if len(s.Permissions) == 0 {
+		return true
+	}
+
+	for _, granted := range s.Permissions {
+		if grantsPermission(granted, permission) {
+			return true
+		}
+	}
+
+	return false
+}
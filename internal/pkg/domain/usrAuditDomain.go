@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/pkg/packhub"
+)
+
+const AuditTableName string = "usr_audit"
+
+// AuditPathLength is the size of the path column.
+const AuditPathLength int = 255
+
+const (
+	AuditActionImpersonate        string = "impersonate"
+	AuditActionImpersonateRequest string = "impersonate_request"
+)
+
+type (
+	// AuditEvent records a sensitive action: ActorAuthID did Action on AuthID, the affected user, from IP.
+	// Requests made while impersonating also keep their Method, Path and response Status.
+	AuditEvent struct {
+		BaseInt
+		Action      string     `gorm:"column:action;type:varchar(50);not null;"`
+		ActorAuthID uint       `gorm:"column:actor_auth_id;type:bigint;not null;"`
+		AuthID      *uint      `gorm:"column:auth_id;type:bigint;"`
+		SessionID   *uuid.UUID `gorm:"column:session_id;type:uuid;"`
+		IP          string     `gorm:"column:ip;type:varchar(45);"`
+		UserAgent   string     `gorm:"column:user_agent;type:varchar(255);"`
+		Method      string     `gorm:"column:method;type:varchar(10);"`
+		Path        string     `gorm:"column:path;type:varchar(255);"`
+		Status      int        `gorm:"column:status;type:smallint;"`
+	}
+
+	AuditRepository interface {
+		CreateAuditEvent(context.Context, *AuditEvent) error
+	}
+)
+
+func (s *AuditEvent) TableName() string { return AuditTableName }
+
+// AuditPath makes the request path fit the path column, dropping invalid UTF-8 and cutting it to
+// AuditPathLength characters.
+func AuditPath(path string) string {
+	return packhub.Truncate(strings.ToValidUTF8(path, ""), AuditPathLength)
+}
@@ -2,12 +2,19 @@ package domain
 
 import (
 	"context"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/raulaguila/go-api/internal/pkg/dto"
 )
 
 const AuthTableName string = "usr_auth"
 
+// MaxLoginBackoffShift caps the doublings of the delay after failed logins, which would otherwise overflow
+// when many attempts are allowed before locking. The delay never exceeds the lock duration anyway.
+const MaxLoginBackoffShift int = 30
+
 type (
 	Auth struct {
 		BaseInt
@@ -16,12 +23,43 @@ type (
 		Profile   *Profile
 		Token     *string `gorm:"column:token;type:varchar(255);unique;index"`
 		Password  *string `gorm:"column:password;type:varchar(255);"`
+
+		PasswordHistory pq.StringArray `gorm:"column:password_history;type:text[];not null;"`
+
+		TOTPSecret        *string        `gorm:"column:totp_secret;type:varchar(64);"`
+		TOTPEnabled       bool           `gorm:"column:totp_enabled;type:bool;not null;"`
+		TOTPLastStep      int64          `gorm:"column:totp_last_step;type:bigint;not null;"`
+		TOTPRecoveryCodes pq.StringArray `gorm:"column:totp_recovery_codes;type:text[];not null;"`
+
+		FailedLogins int        `gorm:"column:failed_logins;type:integer;not null;"`
+		LockedUntil  *time.Time `gorm:"column:locked_until;type:timestamptz;"`
+	}
+
+	// Authenticator verifies a login's password, returning its user. A login it doesn't know fails with
+	// gorm.ErrRecordNotFound and a wrong password with utils.ErrInvalidCredentials, along with the login's
+	// user when it knows one, so the failure is counted against it.
+	Authenticator interface {
+		Authenticate(ctx context.Context, login, password string) (*User, error)
 	}
 
 	AuthService interface {
 		Login(context.Context, *dto.AuthInputDTO) (*dto.AuthOutputDTO, error)
-		Refresh(*User, bool) *dto.AuthOutputDTO
+		Refresh(context.Context, *User, *Session, string, bool) (*dto.AuthOutputDTO, error)
 		Me(*User) *dto.UserOutputDTO
+		Logout(context.Context, *Session) error
+		LogoutAll(context.Context, *User) error
+		GetSessions(context.Context, *User, *Session) ([]dto.SessionOutputDTO, error)
+		RevokeSession(context.Context, *User, string) error
+		EnrollTwoFactor(context.Context, *User) (*dto.TwoFactorEnrollOutputDTO, error)
+		ConfirmTwoFactor(context.Context, *User, string) (*dto.RecoveryCodesOutputDTO, error)
+		DisableTwoFactor(context.Context, *User, string) error
+		VerifyTwoFactor(context.Context, *dto.TwoFactorVerifyInputDTO) (*dto.AuthOutputDTO, error)
+		OIDCAuthorization(context.Context, bool) (string, string, error)
+		OIDCLogin(context.Context, *dto.OIDCCallbackInputDTO) (*dto.AuthOutputDTO, error)
+		OIDCLinkAuthorization(context.Context, *User) (string, string, error)
+		OIDCLink(context.Context, *dto.OIDCCallbackInputDTO) error
+		LDAPLink(context.Context, *User, *dto.LDAPLinkInputDTO) error
+		Impersonate(context.Context, *User, uint, string, string) (*dto.AuthOutputDTO, error)
 	}
 )
 
@@ -33,5 +71,25 @@ func (s *Auth) ToMap() *map[string]any {
 		"profile_id": s.ProfileID,
 		"token":      s.Token,
 		"password":   s.Password,
+
+		"password_history": s.PasswordHistory,
+
+		"totp_secret":         s.TOTPSecret,
+		"totp_enabled":        s.TOTPEnabled,
+		"totp_last_step":      s.TOTPLastStep,
+		"totp_recovery_codes": s.TOTPRecoveryCodes,
+
+		"failed_logins": s.FailedLogins,
+		"locked_until":  s.LockedUntil,
 	}
 }
+
+// Locked reports whether logins are currently refused, either backing off after a failure or locked.
+func (s *Auth) Locked() bool {
+	return s.LockedUntil != nil && s.LockedUntil.After(time.Now())
+}
+
+func (s *Auth) ResetFailedLogins() {
+	s.FailedLogins = 0
+	s.LockedUntil = nil
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/raulaguila/go-api/pkg/keyset"
+)
+
+func TestTokenIssuerPurpose(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := keyset.NewKey(privateKey, "EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := keyset.New(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessTokens := &TokenIssuer{Keys: keys, Issuer: "api", Audience: "app"}
+	twoFactorTokens := accessTokens.Purpose(TwoFactorChallengeType)
+	if twoFactorTokens.Audience != "app#mfa" {
+		t.Errorf("expected: %v, got: %v", "app#mfa", twoFactorTokens.Audience)
+	}
+
+	// Signed as access tokens, so only the audience can tell them apart.
+	token, err := twoFactorTokens.Sign(newClaims(TokenTypeAccess, "1", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := accessTokens.Parse(token, TokenTypeAccess); err == nil {
+		t.Error("expected the access issuer to refuse a token of another purpose")
+	}
+
+	if _, err := twoFactorTokens.Parse(token, TokenTypeAccess); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/raulaguila/go-api/pkg/packhub"
+)
+
+const (
+	IdentityTableName string = "usr_identity"
+
+	// TokenTypeOIDCState is the type of the token kept in a cookie along an OpenID Connect login,
+	// carrying its state, nonce and PKCE verifier.
+	TokenTypeOIDCState  string = "oidc_state"
+	OIDCStateExpiration        = 10 * time.Minute
+)
+
+type (
+	// Identity links a user to its subject at an external issuer, an OpenID Connect provider or a directory.
+	Identity struct {
+		BaseInt
+		AuthID      uint       `gorm:"column:auth_id;type:bigint;not null;index;"`
+		Issuer      string     `gorm:"column:issuer;type:varchar(255);not null;"`
+		Subject     string     `gorm:"column:subject;type:varchar(255);not null;"`
+		Email       string     `gorm:"column:mail;type:varchar(255);not null;"`
+		LastLoginAt *time.Time `gorm:"column:last_login_at;type:timestamptz;"`
+	}
+
+	// ExternalUser is a user authenticated by an external provider, where Subject identifies it at Issuer.
+	// Profile is the name of the local profile the provider's rules grant it, if any.
+	ExternalUser struct {
+		Issuer        string
+		Subject       string
+		Username      string
+		Email         string
+		EmailVerified bool
+		Name          string
+		Profile       string
+	}
+
+	IdentityRepository interface {
+		GetIdentity(context.Context, *Identity) error
+		CreateIdentity(context.Context, *Identity) error
+		TouchIdentity(context.Context, *Identity) error
+		CountIdentities(context.Context, uint) (int64, error)
+	}
+)
+
+func (s *Identity) TableName() string { return IdentityTableName }
+
+// NewOIDCState returns the claims of an OpenID Connect login state token. Its jti is the state sent to the provider.
+func NewOIDCState(nonce, verifier string, expiration bool) *Claims {
+	claims := newClaims(TokenTypeOIDCState, "", packhub.Pointer(OIDCStateExpiration))
+	claims.Nonce, claims.Verifier, claims.Expiration = nonce, verifier, &expiration
+	return claims
+}
+
+// NewOIDCLinkState returns the claims of the state token of an OpenID Connect login linking its identity
+// to the user, who is its subject.
+func (s *User) NewOIDCLinkState(nonce, verifier string) *Claims {
+	claims := newClaims(TokenTypeOIDCState, s.Subject(), packhub.Pointer(OIDCStateExpiration))
+	claims.Token, claims.Nonce, claims.Verifier = packhub.PointerValue(s.Auth.Token, ""), nonce, verifier
+	return claims
+}
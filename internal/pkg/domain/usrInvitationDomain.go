@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/pkg/packhub"
+)
+
+const TokenTypeInvitation string = "invite"
+
+// GenerateInvitationToken signs the token a new user accepts to set its first password. It is bound to
+// the user's auth token, so it stops working once the password is set or a newer invitation is sent, and
+// must be signed by an issuer of its own purpose, so it is never accepted as an access token.
+func (s *User) GenerateInvitationToken(expire time.Duration, issuer *TokenIssuer) (string, error) {
+	if s.Auth.Token == nil {
+		s.Auth.Token = packhub.Pointer(uuid.New().String())
+	}
+
+	claims := newClaims(TokenTypeInvitation, s.Subject(), &expire)
+	claims.Token = *s.Auth.Token
+	return issuer.Sign(claims)
+}
+
+// MatchesInvitation reports whether the invitation claims are still valid for the user.
+func (s *User) MatchesInvitation(claims *Claims) bool {
+	return s.Auth.Password == nil && s.Auth.Token != nil && claims.Subject == s.Subject() && claims.Token == *s.Auth.Token
+}
+
+// VerifyEmail marks the current email as verified, after the user proved receiving mail sent to it.
+func (s *User) VerifyEmail() {
+	s.EmailVerifiedAt = packhub.Pointer(time.Now())
+}
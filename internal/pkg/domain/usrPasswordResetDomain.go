@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const PasswordResetTableName string = "usr_password_reset"
+
+type (
+	// PasswordReset is a single use password reset request. Only the hash of the mailed token is stored.
+	PasswordReset struct {
+		BaseInt
+		AuthID    uint       `gorm:"column:auth_id;type:bigint;not null;index;"`
+		TokenHash string     `gorm:"column:token_hash;type:varchar(64);unique;not null;"`
+		ExpiresAt time.Time  `gorm:"column:expires_at;type:timestamptz;not null;"`
+		UsedAt    *time.Time `gorm:"column:used_at;type:timestamptz;"`
+	}
+
+	PasswordResetRepository interface {
+		CreatePasswordReset(context.Context, *PasswordReset) error
+		GetPasswordReset(context.Context, *PasswordReset) error
+		UsePasswordReset(context.Context, *PasswordReset, *User) error
+	}
+)
+
+func (s *PasswordReset) TableName() string { return PasswordResetTableName }
@@ -0,0 +1,90 @@
+package domain
+
+import "strings"
+
+// Permissions are written as "<resource>:<verb>". A profile holding the bare
+// resource name (e.g. "users") or "<resource>:*" is granted every verb on it,
+// but the explicit ones, which must be granted by name.
+const (
+	PermissionUsers    string = "users"
+	PermissionProfiles string = "profiles"
+
+	PermissionRead        string = "read"
+	PermissionWrite       string = "write"
+	PermissionImpersonate string = "impersonate"
+
+	PermUsersRead        = PermissionUsers + ":" + PermissionRead
+	PermUsersWrite       = PermissionUsers + ":" + PermissionWrite
+	PermUsersImpersonate = PermissionUsers + ":" + PermissionImpersonate
+	PermProfilesRead     = PermissionProfiles + ":" + PermissionRead
+	PermProfilesWrite    = PermissionProfiles + ":" + PermissionWrite
+)
+
+// Permission describes a permission profiles may be granted. Group is the resource it acts on and
+// Label the locale key of its display name. Explicit permissions escalate privileges, so they aren't
+// granted by the group's wildcards.
+type Permission struct {
+	Name        string
+	Description string
+	Label       string
+	Group       string
+	Explicit    bool
+}
+
+var permissionCatalog []Permission
+
+func init() {
+	RegisterPermission(Permission{Name: PermUsersRead, Group: PermissionUsers, Label: "permUsersRead", Description: "List and view users."})
+	RegisterPermission(Permission{Name: PermUsersWrite, Group: PermissionUsers, Label: "permUsersWrite", Description: "Create, update, invite, unlock and delete users."})
+	RegisterPermission(Permission{Name: PermUsersImpersonate, Group: PermissionUsers, Label: "permUsersImpersonate", Description: "Act on behalf of other users.", Explicit: true})
+	RegisterPermission(Permission{Name: PermProfilesRead, Group: PermissionProfiles, Label: "permProfilesRead", Description: "List and view profiles."})
+	RegisterPermission(Permission{Name: PermProfilesWrite, Group: PermissionProfiles, Label: "permProfilesWrite", Description: "Create, update and delete profiles."})
+}
+
+// RegisterPermission adds the permission to the catalog profiles are validated against. It is meant
+// to be called from init functions, so registering a name twice panics.
+func RegisterPermission(permission Permission) {
+	if KnownPermission(permission.Name) {
+		panic("permission " + permission.Name + " registered twice")
+	}
+
+	permissionCatalog = append(permissionCatalog, permission)
+}
+
+// PermissionCatalog returns the registered permissions in registration order.
+func PermissionCatalog() []Permission {
+	return append([]Permission(nil), permissionCatalog...)
+}
+
+// KnownPermission reports whether the permission is registered, or grants every verb of a
+// registered group.
+func KnownPermission(name string) bool {
+	for _, permission := range permissionCatalog {
+		if name == permission.Name || name == permission.Group || name == permission.Group+":*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// explicitPermission reports whether the permission is registered as explicit.
+func explicitPermission(name string) bool {
+	for _, permission := range permissionCatalog {
+		if name == permission.Name {
+			return permission.Explicit
+		}
+	}
+
+	return false
+}
+
+// grantsPermission reports whether the granted permission satisfies the required one.
+func grantsPermission(granted, required string) bool {
+	if granted == required {
+		return true
+	}
+
+	resource, _, _ := strings.Cut(required, ":")
+	return (granted == resource || granted == resource+":*") && !explicitPermission(required)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		granted    []string
+		permission string
+		expected   bool
+	}{
+		{"exact", []string{PermUsersRead}, PermUsersRead, true},
+		{"other_verb", []string{PermUsersRead}, PermUsersWrite, false},
+		{"resource", []string{PermissionUsers}, PermUsersWrite, true},
+		{"resource_wildcard", []string{PermissionUsers + ":*"}, PermUsersRead, true},
+		{"other_resource", []string{PermissionProfiles}, PermUsersRead, false},
+		{"impersonate_exact", []string{PermUsersImpersonate}, PermUsersImpersonate, true},
+		{"impersonate_from_resource", []string{PermissionUsers}, PermUsersImpersonate, false},
+		{"impersonate_from_resource_wildcard", []string{PermissionUsers + ":*"}, PermUsersImpersonate, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &Profile{Permissions: pq.StringArray(tt.granted)}
+			if got := profile.HasPermission(tt.permission); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
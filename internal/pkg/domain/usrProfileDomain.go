@@ -7,6 +7,8 @@ import (
 
 	"github.com/raulaguila/go-api/internal/pkg/dto"
 	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
+	"github.com/raulaguila/go-api/pkg/utils"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
@@ -17,12 +19,17 @@ type (
 		BaseInt
 		Name        string         `gorm:"column:name;type:varchar(100);unique;not null;" validate:"required,min=4"`
 		Permissions pq.StringArray `gorm:"column:permissions;type:text[];not null;" validate:"required"`
+		ParentID    *uint          `gorm:"column:parent_id;type:bigint;"`
+
+		// EffectivePermissions are Permissions plus the ones inherited from the parents, once resolved by Inherit.
+		EffectivePermissions pq.StringArray `gorm:"-"`
 	}
 
 	ProfileRepository interface {
 		CountProfiles(ctx context.Context, f *dto.ProfileFilter) (int64, error)
 		GetProfile(ctx context.Context, p *Profile) error
-		GetProfiles(ctx context.Context, f *dto.ProfileFilter) (*[]Profile, error)
+		GetProfileAncestors(ctx context.Context, id uint) (*[]Profile, error)
+		GetProfiles(ctx context.Context, f *dto.ProfileFilter) (*[]Profile, *pgfilter.Cursors, error)
 		CreateProfile(ctx context.Context, p *Profile) error
 		UpdateProfile(ctx context.Context, p *Profile) error
 		DeleteProfiles(ctx context.Context, i []uint) error
@@ -30,6 +37,7 @@ type (
 
 	ProfileService interface {
 		GenerateProfileOutputDTO(p *Profile) *dto.ProfileOutputDTO
+		GetPermissions(ctx context.Context) []dto.PermissionOutputDTO
 		GetProfiles(ctx context.Context, f *dto.ProfileFilter) (*dto.ItemsOutputDTO[dto.ProfileOutputDTO], error)
 		CreateProfile(ctx context.Context, pdto *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
 		UpdateProfile(ctx context.Context, id uint, pdto *dto.ProfileInputDTO) (*dto.ProfileOutputDTO, error)
@@ -45,14 +53,100 @@ func (s *Profile) ToMap() *map[string]any {
 	return &map[string]any{
 		"name":        s.Name,
 		"permissions": s.Permissions,
+		"parent_id":   s.ParentID,
+	}
+}
+
+// Inherit resolves the effective permissions from profiles, which must hold the profile's ancestors.
+// A parent already visited ends the walk, so cycles in the stored data can't loop forever.
+func (s *Profile) Inherit(profiles []Profile) {
+	byID := make(map[uint]*Profile, len(profiles))
+	for i := range profiles {
+		byID[profiles[i].ID] = &profiles[i]
+	}
+
+	seen := map[string]bool{}
+	visited := map[uint]bool{s.ID: true}
+	s.EffectivePermissions = pq.StringArray{}
+	for profile := s; profile != nil; {
+		for _, permission := range profile.Permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				s.EffectivePermissions = append(s.EffectivePermissions, permission)
+			}
+		}
+
+		if profile.ParentID == nil || visited[*profile.ParentID] {
+			break
+		}
+		visited[*profile.ParentID] = true
+		profile = byID[*profile.ParentID]
 	}
 }
 
+// CheckParent makes sure the profile's parent exists and doesn't descend from the profile, given ancestors,
+// the parent followed by its own parents, resolving the effective permissions on the way.
+func (s *Profile) CheckParent(ancestors []Profile) error {
+	if s.ParentID == nil {
+		s.Inherit(nil)
+		return nil
+	}
+
+	if len(ancestors) == 0 {
+		return utils.ErrProfileParentNotFound
+	}
+
+	for _, ancestor := range ancestors {
+		if ancestor.ID == s.ID {
+			return utils.ErrProfileCycle
+		}
+	}
+
+	s.Inherit(ancestors)
+	return nil
+}
+
+// HasPermission checks the effective permissions once resolved, the profile's own ones otherwise.
+func (s *Profile) HasPermission(permission string) bool {
+	permissions := s.Permissions
+	if s.EffectivePermissions != nil {
+		permissions = s.EffectivePermissions
+	}
+
+	for _, granted := range permissions {
+		if grantsPermission(granted, permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *Profile) Bind(p *dto.ProfileInputDTO) error {
 	if p != nil {
 		s.Name = packhub.PointerValue(p.Name, s.Name)
 		s.Permissions = packhub.PointerValue(p.Permissions, s.Permissions)
+		if p.ParentID != nil {
+			s.ParentID = p.ParentID
+			if *p.ParentID == 0 {
+				s.ParentID = nil
+			}
+		}
+	}
+
+	if s.ParentID != nil && *s.ParentID == s.ID {
+		return utils.ErrProfileCycle
+	}
+
+	if err := validator.StructValidator.Validate(s); err != nil {
+		return err
+	}
+
+	for _, permission := range s.Permissions {
+		if !KnownPermission(permission) {
+			return utils.ErrUnknownPermission
+		}
 	}
 
-	return validator.StructValidator.Validate(s)
+	return nil
 }
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/pkg/packhub"
+)
+
+const SessionTableName string = "usr_session"
+
+// UserAgentLength is the size of the user_agent columns.
+const UserAgentLength int = 255
+
+type (
+	// Session is a refresh token family. Every refresh rotates TokenID, so a refresh token whose jti
+	// no longer matches TokenID has already been used and revokes the whole family. Sessions opened by
+	// an impersonating user have its ActorAuthID and can't be refreshed.
+	Session struct {
+		BaseUUID
+		AuthID     uint       `gorm:"column:auth_id;type:bigint;not null;index;"`
+		TokenID    uuid.UUID  `gorm:"column:token_id;type:uuid;not null;"`
+		ExpiresAt  *time.Time `gorm:"column:expires_at;type:timestamptz;"`
+		RevokedAt  *time.Time `gorm:"column:revoked_at;type:timestamptz;"`
+		IP         string     `gorm:"column:ip;type:varchar(45);"`
+		UserAgent  string     `gorm:"column:user_agent;type:varchar(255);"`
+		LastUsedAt *time.Time `gorm:"column:last_used_at;type:timestamptz;"`
+
+		ActorAuthID *uint `gorm:"column:actor_auth_id;type:bigint;"`
+	}
+
+	SessionRepository interface {
+		GetSession(context.Context, *Session) error
+		GetSessions(context.Context, uint) (*[]Session, error)
+		CreateSession(context.Context, *Session) error
+		TouchSession(context.Context, *Session) error
+		RotateSession(context.Context, *Session, uuid.UUID) error
+		RevokeSession(context.Context, uuid.UUID) error
+		RevokeSessions(context.Context, uint) error
+	}
+)
+
+func (s *Session) TableName() string { return SessionTableName }
+
+// UserAgent makes the client's User-Agent header fit the user_agent columns, dropping invalid UTF-8 and
+// cutting it to UserAgentLength characters, as a longer header would fail every write.
+func UserAgent(userAgent string) string {
+	return packhub.Truncate(strings.ToValidUTF8(userAgent, ""), UserAgentLength)
+}
+
+// SessionTouchInterval limits how often a session's last use is written back while it is being used.
+const SessionTouchInterval = time.Minute
+
+func (s *Session) Active() bool {
+	return s.RevokedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(time.Now()))
+}
+
+func (s *Session) Impersonated() bool {
+	return s.ActorAuthID != nil
+}
+
+func (s *Session) NeedsTouch() bool {
+	return s.LastUsedAt == nil || time.Since(*s.LastUsedAt) >= SessionTouchInterval
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/secret"
+	"github.com/raulaguila/go-api/pkg/totp"
+)
+
+const (
+	TwoFactorChallengeType       string = "mfa"
+	TwoFactorChallengeExpiration        = 5 * time.Minute
+
+	recoveryCodesCount = 10
+)
+
+// EnrollTwoFactor stores a new TOTP secret. It only protects logins after being confirmed with EnableTwoFactor.
+func (s *User) EnrollTwoFactor() (string, error) {
+	totpSecret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	s.Auth.TOTPSecret = &totpSecret
+	s.Auth.TOTPEnabled = false
+	s.Auth.TOTPLastStep = 0
+	s.Auth.TOTPRecoveryCodes = []string{}
+	return totpSecret, nil
+}
+
+// EnableTwoFactor turns the enrolled secret on and returns a new set of single use recovery codes.
+// Only their hashes are kept, so they can't be shown again.
+func (s *User) EnableTwoFactor() ([]string, error) {
+	codes := make([]string, recoveryCodesCount)
+	hashes := make(pq.StringArray, recoveryCodesCount)
+	for i := range codes {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = secret.Hash(code)
+	}
+
+	s.Auth.TOTPEnabled = true
+	s.Auth.TOTPRecoveryCodes = hashes
+	return codes, nil
+}
+
+func (s *User) ResetTwoFactor() {
+	s.Auth.TOTPSecret = nil
+	s.Auth.TOTPEnabled = false
+	s.Auth.TOTPLastStep = 0
+	s.Auth.TOTPRecoveryCodes = []string{}
+}
+
+// ValidateTwoFactorCode accepts a TOTP code newer than the last accepted one, or consumes a recovery code.
+// The caller must persist the user afterward, as both cases change its state.
+func (s *User) ValidateTwoFactorCode(code string) bool {
+	if s.Auth.TOTPSecret == nil {
+		return false
+	}
+
+	code = strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	if step, ok := totp.Validate(*s.Auth.TOTPSecret, code, time.Now(), 1); ok && step > s.Auth.TOTPLastStep {
+		s.Auth.TOTPLastStep = step
+		return true
+	}
+
+	hash := secret.Hash(code)
+	for i, recoveryCode := range s.Auth.TOTPRecoveryCodes {
+		if secret.Equal(recoveryCode, hash) {
+			s.Auth.TOTPRecoveryCodes = slices.Delete(s.Auth.TOTPRecoveryCodes, i, i+1)
+			return true
+		}
+	}
+
+	return false
+}
+
+// GenerateTwoFactorToken signs the short-lived challenge exchanged, along with a code, for the session tokens.
+// It carries no session and must be signed by an issuer of its own purpose, so it is never accepted as an
+// access or refresh token.
+func (s *User) GenerateTwoFactorToken(expiration bool, issuer *TokenIssuer) (string, error) {
+	claims := newClaims(TwoFactorChallengeType, s.Subject(), packhub.Pointer(TwoFactorChallengeExpiration))
+	claims.Token = packhub.PointerValue(s.Auth.Token, "")
+	claims.Expiration = &expiration
+	return issuer.Sign(claims)
+}
@@ -2,16 +2,19 @@ package domain
 
 import (
 	"context"
-	"crypto/rsa"
+	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/hasher"
 	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/passpolicy"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
+	"github.com/raulaguila/go-api/pkg/utils"
 	"github.com/raulaguila/go-api/pkg/validator"
 )
 
@@ -25,15 +28,18 @@ type (
 		Email    string `gorm:"column:mail;" validate:"required,email"`
 		AuthID   uint   `gorm:"column:auth_id;"`
 		Auth     *Auth  `gorm:"constraint:OnDelete:CASCADE"`
+
+		EmailVerifiedAt *time.Time `gorm:"column:mail_verified_at;type:timestamptz;"`
 	}
 
 	UserRepository interface {
 		CountUsers(context.Context, *dto.UserFilter) (int64, error)
-		GetUsers(context.Context, *dto.UserFilter) (*[]User, error)
+		GetUsers(context.Context, *dto.UserFilter) (*[]User, *pgfilter.Cursors, error)
 		GetUser(context.Context, *User) error
 		GetUserByToken(context.Context, string) (*User, error)
 		CreateUser(context.Context, *User) error
 		UpdateUser(context.Context, *User) error
+		RegisterFailedLogin(context.Context, *Auth, int, time.Duration) error
 		DeleteUsers(context.Context, []uint) error
 	}
 
@@ -44,7 +50,12 @@ type (
 		UpdateUser(context.Context, uint, *dto.UserInputDTO) (*dto.UserOutputDTO, error)
 		DeleteUsers(context.Context, []uint) error
 		ResetUserPassword(context.Context, string) error
-		SetUserPassword(context.Context, string, *dto.PasswordInputDTO) error
+		InviteUser(context.Context, uint) error
+		AcceptInvitation(context.Context, *dto.InvitationInputDTO) error
+		RequestPasswordReset(context.Context, string) error
+		ConfirmPasswordReset(context.Context, *dto.PasswordResetInputDTO) error
+		ResetUserTwoFactor(context.Context, uint) error
+		UnlockUser(context.Context, uint) error
 	}
 )
 
@@ -58,11 +69,12 @@ func (s *User) TableName() string {
 
 func (s *User) ToMap() *map[string]any {
 	return &map[string]any{
-		"name":     s.Name,
-		"username": s.Username,
-		"mail":     s.Email,
-		"auth_id":  s.AuthID,
-		"Auth":     *s.Auth.ToMap(),
+		"name":             s.Name,
+		"username":         s.Username,
+		"mail":             s.Email,
+		"mail_verified_at": s.EmailVerifiedAt,
+		"auth_id":          s.AuthID,
+		"Auth":             *s.Auth.ToMap(),
 	}
 }
 
@@ -70,7 +82,11 @@ func (s *User) Bind(p *dto.UserInputDTO) error {
 	if p != nil {
 		s.Name = packhub.PointerValue(p.Name, s.Name)
 		s.Username = packhub.PointerValue(p.Username, s.Username)
-		s.Email = packhub.PointerValue(p.Email, s.Email)
+
+		// A new email has to be verified again.
+		if email := packhub.PointerValue(p.Email, s.Email); email != s.Email {
+			s.Email, s.EmailVerifiedAt = email, nil
+		}
 
 		s.Auth.Status = packhub.PointerValue(p.Status, s.Auth.Status)
 		s.Auth.ProfileID = packhub.PointerValue(p.ProfileID, s.Auth.ProfileID)
@@ -79,41 +95,97 @@ func (s *User) Bind(p *dto.UserInputDTO) error {
 	return validator.StructValidator.Validate(s)
 }
 
-func (s *User) SetPassword(password string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func (s *User) HasPermission(permission string) bool {
+	if s.Auth == nil || s.Auth.Profile == nil {
+		return false
+	}
+
+	return s.Auth.Profile.HasPermission(permission)
+}
+
+// SetPassword replaces the password once it follows the policy and isn't the current one or one of
+// the last policy.History ones. The replaced hash is kept in the password history.
+func (s *User) SetPassword(password string, policy *passpolicy.Policy, passwordHasher hasher.PasswordHasher) error {
+	if err := policy.Validate(password, s.Username, s.Email); err != nil {
+		return err
+	}
+
+	if s.ValidatePassword(password, passwordHasher) {
+		return utils.ErrPasswordReused
+	}
+
+	for _, previous := range s.Auth.PasswordHistory {
+		if passwordHasher.Verify(previous, password) {
+			return utils.ErrPasswordReused
+		}
+	}
+
+	hash, err := passwordHasher.Hash(password)
 	if err != nil {
 		return err
 	}
 
+	if s.Auth.Password != nil && policy.History > 0 {
+		s.Auth.PasswordHistory = append(pq.StringArray{*s.Auth.Password}, s.Auth.PasswordHistory...)
+	}
+	s.Auth.PasswordHistory = s.Auth.PasswordHistory[:min(len(s.Auth.PasswordHistory), policy.History)]
+
 	s.Auth.Token = packhub.Pointer(uuid.New().String())
-	s.Auth.Password = packhub.Pointer(string(hash))
+	s.Auth.Password = &hash
 
 	return nil
 }
 
-func (s *User) ResetPassword() {
-	s.Auth.Token = nil
-	s.Auth.Password = nil
-}
-
-func (s *User) ValidatePassword(password string) bool {
+func (s *User) ValidatePassword(password string, passwordHasher hasher.PasswordHasher) bool {
 	if s.Auth.Password == nil {
 		return false
 	}
 
-	return bcrypt.CompareHashAndPassword([]byte(*s.Auth.Password), []byte(password)) == nil
+	return passwordHasher.Verify(*s.Auth.Password, password)
 }
 
-func (s *User) GenerateToken(expire *time.Duration, parsedToken *rsa.PrivateKey) (string, error) {
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"token": s.Auth.Token,
-		"iat":   now.Unix(),
+// RehashPassword replaces a hash made with outdated parameters by one of passwordHasher, reporting
+// whether it did. Unlike SetPassword, the user's token and so its sessions are kept.
+func (s *User) RehashPassword(password string, passwordHasher hasher.PasswordHasher) (bool, error) {
+	if s.Auth.Password == nil || !passwordHasher.NeedsRehash(*s.Auth.Password) {
+		return false, nil
 	}
 
-	if expire != nil {
-		claims["exp"] = now.Add(*expire).Unix()
+	hash, err := passwordHasher.Hash(password)
+	if err != nil {
+		return false, err
+	}
+
+	s.Auth.Password = &hash
+	return true, nil
+}
+
+// Subject is the user's id as carried by the sub claim of its tokens.
+func (s *User) Subject() string {
+	return strconv.FormatUint(uint64(s.ID), 10)
+}
+
+// GenerateToken signs a token of the given type bound to the session. Refresh tokens carry the
+// session's current token id as jti, which is what the next rotation checks against.
+func (s *User) GenerateToken(session *Session, tokenType string, expire *time.Duration, issuer *TokenIssuer) (string, error) {
+	claims := newClaims(tokenType, s.Subject(), expire)
+	claims.Token = packhub.PointerValue(s.Auth.Token, "")
+	claims.SessionID = session.ID.String()
+
+	if tokenType == TokenTypeRefresh {
+		claims.ID = session.TokenID.String()
 	}
 
-	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(parsedToken)
+	return issuer.Sign(claims)
+}
+
+// GenerateImpersonationToken signs an access token for the user on behalf of actor, bound to the
+// impersonation session. It always expires, and no refresh token goes with it.
+func (s *User) GenerateImpersonationToken(session *Session, actor *User, expire time.Duration, issuer *TokenIssuer) (string, error) {
+	claims := newClaims(TokenTypeAccess, s.Subject(), &expire)
+	claims.Token = packhub.PointerValue(s.Auth.Token, "")
+	claims.SessionID = session.ID.String()
+	claims.Actor = &Actor{Subject: actor.Subject()}
+
+	return issuer.Sign(claims)
 }
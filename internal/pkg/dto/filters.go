@@ -21,8 +21,9 @@ type (
 
 	UserFilter struct {
 		pgfilter.Filter
-		ProfileID uint  `query:"profile_id" form:"level_id" example:"1"`
-		Status    *bool `query:"status" form:"status" example:"false"`
+		ProfileID  uint   `query:"profile_id" form:"level_id" example:"1"`
+		Status     *bool  `query:"status" form:"status" example:"false"`
+		SearchMode string `query:"search_mode" form:"search_mode" enums:"contains,fulltext,trigram" default:"contains"`
 	}
 
 	EvidenceFilter struct {
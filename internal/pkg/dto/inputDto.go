@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/lib/pq"
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
 
 type (
 	IDInputDTO[T uint | string] struct {
@@ -14,6 +18,7 @@ type (
 	ProfileInputDTO struct {
 		Name        *string         `json:"name" example:"ADMIN"`
 		Permissions *pq.StringArray `json:"permissions"`
+		ParentID    *uint           `json:"parent_id" example:"1"`
 	}
 
 	UserInputDTO struct {
@@ -24,7 +29,18 @@ type (
 		ProfileID *uint   `json:"profile_id" example:"1"`
 	}
 
-	PasswordInputDTO struct {
+	InvitationInputDTO struct {
+		Token           *string `json:"token" example:"eyJhbGciOiJSUzI1NiIsImtpZCI6IjEifQ..."`
+		Password        *string `json:"password" example:"secret"`
+		PasswordConfirm *string `json:"password_confirm" example:"secret"`
+	}
+
+	PasswordResetRequestDTO struct {
+		Email *string `json:"email" example:"john.cena@email.com"`
+	}
+
+	PasswordResetInputDTO struct {
+		Token           *string `json:"token" example:"Xb8rH3c1bJ0yZfQhPqUuKdVwN2mTzA5sR7eGkLiOj4o"`
 		Password        *string `json:"password" example:"secret"`
 		PasswordConfirm *string `json:"password_confirm" example:"secret"`
 	}
@@ -33,5 +49,40 @@ type (
 		Login      string `json:"login" example:"admin"`
 		Password   string `json:"password" example:"12345678"`
 		Expiration bool   `json:"expiration" example:"true" default:"true"`
+		Cookie     bool   `json:"cookie" example:"false"`
+		IP         string `json:"-"`
+		UserAgent  string `json:"-"`
+	}
+
+	TwoFactorCodeInputDTO struct {
+		Code *string `json:"code" example:"123456"`
+	}
+
+	TwoFactorVerifyInputDTO struct {
+		Token     *string `json:"mfa_token"`
+		Code      *string `json:"code" example:"123456"`
+		Cookie    bool    `json:"cookie" example:"false"`
+		IP        string  `json:"-"`
+		UserAgent string  `json:"-"`
+	}
+
+	OIDCCallbackInputDTO struct {
+		Code       string
+		State      string
+		StateToken string
+		IP         string
+		UserAgent  string
+	}
+
+	LDAPLinkInputDTO struct {
+		Login    string `json:"login" example:"john.cena"`
+		Password string `json:"password" example:"secret"`
+		IP       string `json:"-"`
+	}
+
+	APIKeyInputDTO struct {
+		Name        *string         `json:"name" example:"nightly-export"`
+		Permissions *pq.StringArray `json:"permissions"`
+		ExpiresAt   *time.Time      `json:"expires_at"`
 	}
 )
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/lib/pq"
 )
 
@@ -9,27 +11,47 @@ type (
 		ID          *uint           `json:"id" example:"1"`
 		Name        *string         `json:"name" example:"ADMIN"`
 		Permissions *pq.StringArray `json:"permissions,omitempty"`
+		ParentID    *uint           `json:"parent_id,omitempty" example:"1"`
+
+		EffectivePermissions *pq.StringArray `json:"effective_permissions,omitempty"`
+	}
+
+	PermissionOutputDTO struct {
+		Name        string `json:"name" example:"users:read"`
+		Label       string `json:"label" example:"Read users"`
+		Description string `json:"description" example:"List and view users."`
+		Group       string `json:"group" example:"users"`
 	}
 
 	UserOutputDTO struct {
-		ID       *uint             `json:"id" example:"1"`
-		Name     *string           `json:"name" example:"John Cena"`
-		Email    *string           `json:"email" example:"john.cena@email.com"`
-		Username *string           `json:"corp_id" example:"john.cena"`
-		Status   *bool             `json:"status" example:"true"`
-		New      *bool             `json:"new,omitempty" example:"true"`
-		Profile  *ProfileOutputDTO `json:"profile,omitempty"`
+		ID            *uint             `json:"id" example:"1"`
+		Name          *string           `json:"name" example:"John Cena"`
+		Email         *string           `json:"email" example:"john.cena@email.com"`
+		EmailVerified *bool             `json:"email_verified,omitempty" example:"true"`
+		Username      *string           `json:"corp_id" example:"john.cena"`
+		Status        *bool             `json:"status" example:"true"`
+		New           *bool             `json:"new,omitempty" example:"true"`
+		TwoFactor     *bool             `json:"two_factor,omitempty" example:"false"`
+		Locked        *bool             `json:"locked,omitempty" example:"false"`
+		Profile       *ProfileOutputDTO `json:"profile,omitempty"`
+
+		// InvitationSent tells, on creation, whether the invitation could be mailed.
+		InvitationSent *bool `json:"invitation_sent,omitempty" example:"true"`
 	}
 
 	outputDTO interface {
 		ProfileOutputDTO | UserOutputDTO
 	}
 
+	// PaginationDTO describes the page read. Pages read by number always have CurrentPage and the totals;
+	// pages read by cursor leave CurrentPage out, and the totals unless asked for.
 	PaginationDTO struct {
-		CurrentPage uint `json:"current_page"`
-		PageSize    uint `json:"page_size"`
-		TotalItems  uint `json:"total_items"`
-		TotalPages  uint `json:"total_pages"`
+		CurrentPage uint   `json:"current_page,omitempty"`
+		PageSize    uint   `json:"page_size"`
+		TotalItems  *uint  `json:"total_items,omitempty"`
+		TotalPages  *uint  `json:"total_pages,omitempty"`
+		NextCursor  string `json:"next_cursor,omitempty"`
+		PrevCursor  string `json:"prev_cursor,omitempty"`
 	}
 
 	ItemsOutputDTO[T outputDTO] struct {
@@ -37,9 +59,47 @@ type (
 		Pagination PaginationDTO `json:"pagination"`
 	}
 
+	SessionOutputDTO struct {
+		ID           string     `json:"id" example:"5f8a3b9e-8f43-4c49-9c1a-0d5b2a1e7c11"`
+		IP           string     `json:"ip" example:"127.0.0.1"`
+		UserAgent    string     `json:"user_agent" example:"Mozilla/5.0"`
+		CreatedAt    time.Time  `json:"created_at"`
+		LastUsedAt   *time.Time `json:"last_used_at"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		Current      bool       `json:"current" example:"true"`
+		Impersonated bool       `json:"impersonated" example:"false"`
+	}
+
 	AuthOutputDTO struct {
 		User         *UserOutputDTO `json:"user,omitempty"`
-		AccessToken  string         `json:"accesstoken"`
-		RefreshToken string         `json:"refreshtoken"`
+		AccessToken  string         `json:"accesstoken,omitempty"`
+		RefreshToken string         `json:"refreshtoken,omitempty"`
+		CSRFToken    string         `json:"csrf_token,omitempty"`
+		MFARequired  bool           `json:"mfa_required,omitempty" example:"false"`
+		MFAToken     string         `json:"mfa_token,omitempty"`
+	}
+
+	TwoFactorEnrollOutputDTO struct {
+		Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+		URI    string `json:"uri" example:"otpauth://totp/Go%20API:admin?algorithm=SHA1&digits=6&issuer=Go+API&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	}
+
+	OIDCLinkOutputDTO struct {
+		URL string `json:"url" example:"https://idp.example.com/authorize?client_id=go-api&response_type=code"`
+	}
+
+	APIKeyOutputDTO struct {
+		ID          uint           `json:"id" example:"1"`
+		Name        string         `json:"name" example:"nightly-export"`
+		Prefix      string         `json:"prefix" example:"3f9a2c1b"`
+		Permissions pq.StringArray `json:"permissions"`
+		Key         string         `json:"key,omitempty" example:"gak_3f9a2c1b_Xb8rH3c1bJ0yZfQhPqUuKdVwN2mTzA5sR7eGkLiOj4o"`
+		CreatedAt   time.Time      `json:"created_at"`
+		ExpiresAt   *time.Time     `json:"expires_at"`
+		LastUsedAt  *time.Time     `json:"last_used_at"`
+	}
+
+	RecoveryCodesOutputDTO struct {
+		RecoveryCodes []string `json:"recovery_codes" example:"abcd-efgh"`
 	}
 )
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+)
+
+func NewAPIKeyRepository(postgreDB *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyRepository{
+		postgreDB: postgreDB,
+	}
+}
+
+type apiKeyRepository struct {
+	postgreDB *gorm.DB
+}
+
+func (s *apiKeyRepository) GetAPIKey(ctx context.Context, input *domain.APIKey) error {
+	return s.postgreDB.WithContext(ctx).Where(input).First(input).Error
+}
+
+func (s *apiKeyRepository) GetAPIKeys(ctx context.Context, authID uint) (*[]domain.APIKey, error) {
+	apiKeys := new([]domain.APIKey)
+	return apiKeys, s.postgreDB.WithContext(ctx).
+		Where("auth_id = ? AND revoked_at IS NULL", authID).
+		Order("created_at DESC").
+		Find(apiKeys).Error
+}
+
+func (s *apiKeyRepository) CreateAPIKey(ctx context.Context, input *domain.APIKey) error {
+	return s.postgreDB.WithContext(ctx).Create(input).Error
+}
+
+func (s *apiKeyRepository) TouchAPIKey(ctx context.Context, input *domain.APIKey) error {
+	return s.postgreDB.WithContext(ctx).Model(input).Update("last_used_at", input.LastUsedAt).Error
+}
+
+// RevokeAPIKey revokes the key matching input.ID and input.AuthID, returning gorm.ErrRecordNotFound
+// when the user has no such active key.
+func (s *apiKeyRepository) RevokeAPIKey(ctx context.Context, input *domain.APIKey) error {
+	result := s.postgreDB.WithContext(ctx).
+		Model(new(domain.APIKey)).
+		Where("id = ? AND auth_id = ? AND revoked_at IS NULL", input.ID, input.AuthID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
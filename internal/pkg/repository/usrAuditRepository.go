@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+)
+
+func NewAuditRepository(postgreDB *gorm.DB) domain.AuditRepository {
+	return &auditRepository{
+		postgreDB: postgreDB,
+	}
+}
+
+type auditRepository struct {
+	postgreDB *gorm.DB
+}
+
+func (s *auditRepository) CreateAuditEvent(ctx context.Context, input *domain.AuditEvent) error {
+	return s.postgreDB.WithContext(ctx).Create(input).Error
+}
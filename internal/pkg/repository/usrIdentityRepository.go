@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+)
+
+func NewIdentityRepository(postgreDB *gorm.DB) domain.IdentityRepository {
+	return &identityRepository{
+		postgreDB: postgreDB,
+	}
+}
+
+type identityRepository struct {
+	postgreDB *gorm.DB
+}
+
+func (s *identityRepository) GetIdentity(ctx context.Context, input *domain.Identity) error {
+	return s.postgreDB.WithContext(ctx).Where(input).First(input).Error
+}
+
+func (s *identityRepository) CreateIdentity(ctx context.Context, input *domain.Identity) error {
+	return s.postgreDB.WithContext(ctx).Create(input).Error
+}
+
+func (s *identityRepository) TouchIdentity(ctx context.Context, input *domain.Identity) error {
+	return s.postgreDB.WithContext(ctx).Model(input).Updates(map[string]any{"mail": input.Email, "last_login_at": input.LastLoginAt}).Error
+}
+
+func (s *identityRepository) CountIdentities(ctx context.Context, authID uint) (int64, error) {
+	var count int64
+	return count, s.postgreDB.WithContext(ctx).Model(new(domain.Identity)).Where("auth_id = ?", authID).Count(&count).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+func NewPasswordResetRepository(postgreDB *gorm.DB) domain.PasswordResetRepository {
+	return &passwordResetRepository{
+		postgreDB: postgreDB,
+	}
+}
+
+type passwordResetRepository struct {
+	postgreDB *gorm.DB
+}
+
+func (s *passwordResetRepository) CreatePasswordReset(ctx context.Context, input *domain.PasswordReset) error {
+	return s.postgreDB.WithContext(ctx).Create(input).Error
+}
+
+// GetPasswordReset loads the pending request matching input.TokenHash into input, returning
+// utils.ErrInvalidToken when it doesn't exist, expired or was already used.
+func (s *passwordResetRepository) GetPasswordReset(ctx context.Context, input *domain.PasswordReset) error {
+	err := s.postgreDB.WithContext(ctx).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", input.TokenHash, time.Now()).
+		First(input).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.ErrInvalidToken
+	}
+	return err
+}
+
+// UsePasswordReset marks the request as used and saves the user, with its new password, in one transaction,
+// discarding the user's other pending requests. When the request was used meanwhile or expired, nothing is
+// saved and utils.ErrInvalidToken is returned.
+func (s *passwordResetRepository) UsePasswordReset(ctx context.Context, input *domain.PasswordReset, user *domain.User) error {
+	return s.postgreDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(input).
+			Where("used_at IS NULL AND expires_at > ?", now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return utils.ErrInvalidToken
+		}
+
+		if err := tx.Model(user.Auth).Updates(user.Auth.ToMap()).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Updates(user.ToMap()).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(new(domain.PasswordReset)).
+			Where("auth_id = ? AND used_at IS NULL", user.AuthID).
+			Update("used_at", now).Error
+	})
+}
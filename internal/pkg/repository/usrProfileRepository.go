@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"gorm.io/gorm"
 
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
 )
 
 func NewProfileRepository(postgreDB *gorm.DB) domain.ProfileRepository {
@@ -15,6 +18,23 @@ func NewProfileRepository(postgreDB *gorm.DB) domain.ProfileRepository {
 	}
 }
 
+// profileSortColumns are the columns profiles may be sorted by.
+var profileSortColumns = pgfilter.SortColumns{
+	"id":         "id",
+	"name":       "name",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// profileFilterFields are the fields profiles may be filtered by.
+var profileFilterFields = pgfilter.FilterFields{
+	"id":         {Column: "id", Type: pgfilter.FieldInteger},
+	"name":       {Column: "name", Type: pgfilter.FieldString},
+	"parent_id":  {Column: "parent_id", Type: pgfilter.FieldInteger},
+	"created_at": {Column: "created_at", Type: pgfilter.FieldTime},
+	"updated_at": {Column: "updated_at", Type: pgfilter.FieldTime},
+}
+
 type profileRepository struct {
 	postgreDB *gorm.DB
 }
@@ -26,18 +46,18 @@ func (s *profileRepository) applyFilter(ctx context.Context, f *dto.ProfileFilte
 			postgreDB = postgreDB.Where("id = ?", *f.ID)
 		}
 
-		if where := f.ApplySearchLike("name"); where != "" {
+		if where := f.ApplySearchLike("name"); where != nil {
 			postgreDB = postgreDB.Where(where)
 		}
-		postgreDB = postgreDB.Order(f.ApplyOrder(nil))
-		if f.WithPermissions != nil && !(*f.WithPermissions) {
-			postgreDB = postgreDB.Omit("permissions")
-		}
 		if !f.ListRoot {
 			postgreDB = postgreDB.Where("name != ?", "ROOT")
 		}
 
-		postgreDB = postgreDB.Order(f.ApplyOrder(nil))
+		if where, err := f.ApplyConditions(profileFilterFields); err != nil {
+			_ = postgreDB.AddError(err)
+		} else if where != nil {
+			postgreDB = postgreDB.Where(where)
+		}
 	}
 
 	return postgreDB.Group("id")
@@ -48,28 +68,95 @@ func (s *profileRepository) CountProfiles(ctx context.Context, f *dto.ProfileFil
 	return count, s.applyFilter(ctx, f).Model(new(domain.Profile)).Count(&count).Error
 }
 
-func (s *profileRepository) GetProfiles(ctx context.Context, f *dto.ProfileFilter) (*[]domain.Profile, error) {
-	postgreDB := s.applyFilter(ctx, f)
-	if f != nil {
-		if ok, offset, limit := f.ApplyPagination(); ok {
-			postgreDB = postgreDB.Offset(offset).Limit(limit)
-		}
+// GetProfiles reads the keys of the page first, then loads its profiles by primary key in the same order.
+// Without filter, every profile is returned.
+func (s *profileRepository) GetProfiles(ctx context.Context, f *dto.ProfileFilter) (*[]domain.Profile, *pgfilter.Cursors, error) {
+	profiles := new([]domain.Profile)
+	if f == nil {
+		return profiles, &pgfilter.Cursors{}, s.postgreDB.WithContext(ctx).Find(profiles).Error
 	}
 
-	profiles := new([]domain.Profile)
-	return profiles, postgreDB.Find(profiles).Error
+	ids, cursors, err := f.ReadPage(s.applyFilter(ctx, f).Model(new(domain.Profile)), profileSortColumns, "id")
+	if err != nil || len(ids) == 0 {
+		return profiles, cursors, err
+	}
+
+	postgreDB := s.postgreDB.WithContext(ctx)
+	if f.WithPermissions != nil && !(*f.WithPermissions) {
+		postgreDB = postgreDB.Omit("permissions")
+	}
+
+	if err := postgreDB.Find(profiles, ids).Error; err != nil {
+		return nil, nil, err
+	}
+
+	position := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+	slices.SortFunc(*profiles, func(a, b domain.Profile) int { return position[a.ID] - position[b.ID] })
+
+	return profiles, cursors, nil
 }
 
 func (s *profileRepository) GetProfile(ctx context.Context, input *domain.Profile) error {
 	return s.postgreDB.WithContext(ctx).Where(input).First(input).Error
 }
 
+// ancestors reads the profile with the given id followed by its parents, up to the root of its hierarchy.
+// UNION drops repeated rows, ending the recursion should the parents form a cycle.
+func ancestors(postgreDB *gorm.DB, id uint, profiles *[]domain.Profile) error {
+	return postgreDB.Raw(fmt.Sprintf(`WITH RECURSIVE ancestors AS (
+		SELECT id, name, permissions, parent_id FROM %[1]v WHERE id = ?
+		UNION
+		SELECT p.id, p.name, p.permissions, p.parent_id FROM %[1]v p JOIN ancestors a ON p.id = a.parent_id
+	) SELECT * FROM ancestors`, domain.ProfileTableName), id).Scan(profiles).Error
+}
+
+// GetProfileAncestors returns the profile with the given id followed by its parents.
+func (s *profileRepository) GetProfileAncestors(ctx context.Context, id uint) (*[]domain.Profile, error) {
+	profiles := new([]domain.Profile)
+	return profiles, ancestors(s.postgreDB.WithContext(ctx), id, profiles)
+}
+
+// checkParent checks the profile's parent within tx, having locked the profiles against concurrent writes
+// first, so no other change can turn the checked parents into a cycle before the profile is written.
+// Reads aren't blocked by the lock.
+func checkParent(tx *gorm.DB, input *domain.Profile) error {
+	if err := tx.Exec(fmt.Sprintf("LOCK TABLE %v IN SHARE ROW EXCLUSIVE MODE", domain.ProfileTableName)).Error; err != nil {
+		return err
+	}
+
+	profiles := new([]domain.Profile)
+	if input.ParentID != nil {
+		if err := ancestors(tx, *input.ParentID, profiles); err != nil {
+			return err
+		}
+	}
+
+	return input.CheckParent(*profiles)
+}
+
+// CreateProfile checks the profile's parent and creates it in one transaction.
 func (s *profileRepository) CreateProfile(ctx context.Context, input *domain.Profile) error {
-	return s.postgreDB.WithContext(ctx).Create(input).Error
+	return s.postgreDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkParent(tx, input); err != nil {
+			return err
+		}
+
+		return tx.Create(input).Error
+	})
 }
 
+// UpdateProfile checks the profile's parent and updates it in one transaction.
 func (s *profileRepository) UpdateProfile(ctx context.Context, input *domain.Profile) error {
-	return s.postgreDB.WithContext(ctx).Model(input).Updates(input.ToMap()).Error
+	return s.postgreDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkParent(tx, input); err != nil {
+			return err
+		}
+
+		return tx.Model(input).Updates(input.ToMap()).Error
+	})
 }
 
 func (s *profileRepository) DeleteProfiles(ctx context.Context, ids []uint) error {
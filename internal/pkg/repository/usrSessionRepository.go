@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+func NewSessionRepository(postgreDB *gorm.DB) domain.SessionRepository {
+	return &sessionRepository{
+		postgreDB: postgreDB,
+	}
+}
+
+type sessionRepository struct {
+	postgreDB *gorm.DB
+}
+
+func (s *sessionRepository) GetSession(ctx context.Context, input *domain.Session) error {
+	return s.postgreDB.WithContext(ctx).Where(input).First(input).Error
+}
+
+func (s *sessionRepository) GetSessions(ctx context.Context, authID uint) (*[]domain.Session, error) {
+	sessions := new([]domain.Session)
+	return sessions, s.postgreDB.WithContext(ctx).
+		Where("auth_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", authID, time.Now()).
+		Order("last_used_at DESC NULLS LAST").
+		Find(sessions).Error
+}
+
+func (s *sessionRepository) CreateSession(ctx context.Context, input *domain.Session) error {
+	return s.postgreDB.WithContext(ctx).Create(input).Error
+}
+
+func (s *sessionRepository) TouchSession(ctx context.Context, input *domain.Session) error {
+	return s.postgreDB.WithContext(ctx).Model(input).Updates(map[string]any{
+		"ip":           input.IP,
+		"user_agent":   input.UserAgent,
+		"last_used_at": input.LastUsedAt,
+	}).Error
+}
+
+// RotateSession replaces the session token id only if the presented one is still the current one,
+// returning utils.ErrTokenReused otherwise. The check and the update happen in a single statement,
+// so two concurrent refreshes with the same token can't both succeed.
+func (s *sessionRepository) RotateSession(ctx context.Context, input *domain.Session, previous uuid.UUID) error {
+	result := s.postgreDB.WithContext(ctx).
+		Model(input).
+		Where("token_id = ? AND revoked_at IS NULL", previous).
+		Updates(map[string]any{
+			"token_id":   input.TokenID,
+			"expires_at": input.ExpiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return utils.ErrTokenReused
+	}
+	return nil
+}
+
+func (s *sessionRepository) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	return s.postgreDB.WithContext(ctx).
+		Model(new(domain.Session)).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (s *sessionRepository) RevokeSessions(ctx context.Context, authID uint) error {
+	return s.postgreDB.WithContext(ctx).
+		Model(new(domain.Session)).
+		Where("auth_id = ? AND revoked_at IS NULL", authID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -3,13 +3,15 @@ package repository
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
-	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
@@ -19,6 +21,34 @@ func NewUserRepository(postgreDB *gorm.DB) domain.UserRepository {
 	}
 }
 
+// userSortColumns are the columns users may be sorted by, including the joined auth and profile ones.
+var userSortColumns = pgfilter.SortColumns{
+	"id":         domain.UserTableName + ".id",
+	"name":       domain.UserTableName + ".name",
+	"username":   domain.UserTableName + ".username",
+	"email":      domain.UserTableName + ".mail",
+	"status":     domain.AuthTableName + ".status",
+	"profile":    domain.ProfileTableName + ".name",
+	"created_at": domain.UserTableName + ".created_at",
+	"updated_at": domain.UserTableName + ".updated_at",
+}
+
+// userFilterFields are the fields users may be filtered by.
+var userFilterFields = pgfilter.FilterFields{
+	"id":         {Column: domain.UserTableName + ".id", Type: pgfilter.FieldInteger},
+	"name":       {Column: domain.UserTableName + ".name", Type: pgfilter.FieldString},
+	"username":   {Column: domain.UserTableName + ".username", Type: pgfilter.FieldString},
+	"email":      {Column: domain.UserTableName + ".mail", Type: pgfilter.FieldString},
+	"status":     {Column: domain.AuthTableName + ".status", Type: pgfilter.FieldBool},
+	"profile":    {Column: domain.ProfileTableName + ".name", Type: pgfilter.FieldString},
+	"profile_id": {Column: domain.AuthTableName + ".profile_id", Type: pgfilter.FieldInteger},
+	"created_at": {Column: domain.UserTableName + ".created_at", Type: pgfilter.FieldTime},
+	"updated_at": {Column: domain.UserTableName + ".updated_at", Type: pgfilter.FieldTime},
+}
+
+// userSearchConfig is the text search configuration the users' search_vector is built with.
+const userSearchConfig = "public.simple_unaccent"
+
 type userRepository struct {
 	postgreDB *gorm.DB
 }
@@ -41,19 +71,47 @@ func (s *userRepository) applyFilter(ctx context.Context, f *dto.UserFilter) *go
 		postgreDB = postgreDB.Joins(fmt.Sprintf("JOIN %v ON %v.id = %v.auth_id", domain.AuthTableName, domain.AuthTableName, domain.UserTableName))
 		postgreDB = postgreDB.Joins(fmt.Sprintf("JOIN %v ON %v.id = %v.profile_id", domain.ProfileTableName, domain.ProfileTableName, domain.AuthTableName))
 
-		if where := f.ApplySearchLike(
+		if where, _, err := s.applySearch(f); err != nil {
+			_ = postgreDB.AddError(err)
+		} else if where != nil {
+			postgreDB = postgreDB.Where(where)
+		}
+
+		if where, err := f.ApplyConditions(userFilterFields); err != nil {
+			_ = postgreDB.AddError(err)
+		} else if where != nil {
+			postgreDB = postgreDB.Where(where)
+		}
+	}
+
+	// Grouping by every joined key lets the rows be sorted by any column of the joined tables.
+	return postgreDB.Group(domain.UserTableName + ".id, " + domain.AuthTableName + ".id, " + domain.ProfileTableName + ".id")
+}
+
+// applySearch returns the condition of the search in its mode and, for the ranked modes, the rank of the rows.
+// Only the contains mode searches the profile name too, as the indexes cover the user columns alone.
+func (s *userRepository) applySearch(f *dto.UserFilter) (where, rank clause.Expression, err error) {
+	switch f.SearchMode {
+	case "", pgfilter.SearchContains:
+		where = f.ApplySearchLike(
 			domain.UserTableName+".name",
 			domain.UserTableName+".username",
 			domain.UserTableName+".mail",
 			domain.ProfileTableName+".name",
-		); where != "" {
-			postgreDB = postgreDB.Where(where)
-		}
-
-		postgreDB = postgreDB.Order(f.ApplyOrder(packhub.Pointer(domain.UserTableName)))
+		)
+	case pgfilter.SearchFullText:
+		where, rank = f.ApplySearchFullText(userSearchConfig, domain.UserTableName+".search_vector")
+	case pgfilter.SearchTrigram:
+		where, rank = f.ApplySearchTrigram(
+			domain.UserTableName+".name",
+			domain.UserTableName+".username",
+			domain.UserTableName+".mail",
+		)
+	default:
+		err = pgfilter.ErrInvalidSearchMode
 	}
 
-	return postgreDB.Group(domain.UserTableName + ".id")
+	return
 }
 
 func (s *userRepository) CountUsers(ctx context.Context, f *dto.UserFilter) (int64, error) {
@@ -61,16 +119,35 @@ func (s *userRepository) CountUsers(ctx context.Context, f *dto.UserFilter) (int
 	return count, s.applyFilter(ctx, f).Model(new(domain.User)).Count(&count).Error
 }
 
-func (s *userRepository) GetUsers(ctx context.Context, f *dto.UserFilter) (*[]domain.User, error) {
-	postgreDB := s.applyFilter(ctx, f)
-	if f != nil {
-		if ok, offset, limit := f.ApplyPagination(); ok {
-			postgreDB = postgreDB.Offset(offset).Limit(limit)
-		}
+// GetUsers reads the keys of the page first, then loads its users by primary key in the same order.
+// Full text and trigram searches are ordered by relevance first.
+func (s *userRepository) GetUsers(ctx context.Context, f *dto.UserFilter) (*[]domain.User, *pgfilter.Cursors, error) {
+	users := new([]domain.User)
+	if f == nil {
+		return users, &pgfilter.Cursors{}, s.postgreDB.WithContext(ctx).Preload(utils.PGAuthProfile).Find(users).Error
 	}
 
-	users := new([]domain.User)
-	return users, postgreDB.Preload(utils.PGAuthProfile).Find(users).Error
+	_, rank, err := s.applySearch(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids, cursors, err := f.ReadRankedPage(s.applyFilter(ctx, f).Model(new(domain.User)), rank, userSortColumns, domain.UserTableName+".id")
+	if err != nil || len(ids) == 0 {
+		return users, cursors, err
+	}
+
+	if err := s.postgreDB.WithContext(ctx).Preload(utils.PGAuthProfile).Find(users, ids).Error; err != nil {
+		return nil, nil, err
+	}
+
+	position := make(map[uint]int, len(ids))
+	for i, id := range ids {
+		position[id] = i
+	}
+	slices.SortFunc(*users, func(a, b domain.User) int { return position[a.ID] - position[b.ID] })
+
+	return users, cursors, nil
 }
 
 func (s *userRepository) GetUser(ctx context.Context, input *domain.User) error {
@@ -100,6 +177,32 @@ func (s *userRepository) UpdateUser(ctx context.Context, input *domain.User) err
 	})
 }
 
+// RegisterFailedLogin counts a failed login in a single statement, so concurrent failures can't overwrite
+// each other's count. Each failure refuses logins for twice as long as the previous one, starting at a second,
+// until maxAttempts failures lock the account for lockDuration. The new count and lock are set on auth.
+func (s *userRepository) RegisterFailedLogin(ctx context.Context, auth *domain.Auth, maxAttempts int, lockDuration time.Duration) error {
+	result := struct {
+		FailedLogins int
+		LockedUntil  *time.Time
+	}{}
+
+	lockSeconds := lockDuration.Seconds()
+	if err := s.postgreDB.WithContext(ctx).Raw(fmt.Sprintf(`UPDATE %v SET
+		failed_logins = failed_logins + 1,
+		locked_until = NOW() + make_interval(secs => CASE
+			WHEN failed_logins + 1 >= ? THEN ?::float8
+			ELSE LEAST(power(2, LEAST(failed_logins, ?)), ?::float8)
+		END)
+		WHERE id = ? RETURNING failed_logins, locked_until`, domain.AuthTableName),
+		maxAttempts, lockSeconds, domain.MaxLoginBackoffShift, lockSeconds, auth.ID,
+	).Scan(&result).Error; err != nil {
+		return err
+	}
+
+	auth.FailedLogins, auth.LockedUntil = result.FailedLogins, result.LockedUntil
+	return nil
+}
+
 func (s *userRepository) DeleteUsers(ctx context.Context, toDelete []uint) error {
 	users := new([]domain.User)
 	if err := s.postgreDB.WithContext(ctx).Find(users, toDelete).Error; err != nil {
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// federation maps users authenticated by external providers to local users.
+type federation struct {
+	repository         domain.UserRepository
+	identityRepository domain.IdentityRepository
+	profileRepository  domain.ProfileRepository
+}
+
+// user returns the local user linked to the external one. Unknown external users are linked to the user
+// with the same, verified, email when linkByEmail allows it, or else provisioned when allowed, with their
+// provider's profile or defaultProfile. The provider's profile is applied on every login.
+func (s *federation) user(ctx context.Context, external *domain.ExternalUser, linkByEmail, provision bool, defaultProfile string) (*domain.User, error) {
+	identity := &domain.Identity{Issuer: external.Issuer, Subject: external.Subject}
+	user := &domain.User{}
+
+	switch err := s.identityRepository.GetIdentity(ctx, identity); {
+	case err == nil:
+		user.AuthID = identity.AuthID
+		if err := s.repository.GetUser(ctx, user); err != nil {
+			return nil, err
+		}
+
+		identity.Email, identity.LastLoginAt = external.Email, packhub.Pointer(time.Now())
+		if err := s.identityRepository.TouchIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if user, err = s.linkedUser(ctx, external, linkByEmail, provision, defaultProfile); err != nil {
+			return nil, err
+		}
+
+		identity.AuthID, identity.Email, identity.LastLoginAt = user.AuthID, external.Email, packhub.Pointer(time.Now())
+		if err := s.identityRepository.CreateIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	changed := false
+	if external.Profile != "" && external.Profile != user.Auth.Profile.Name {
+		profile := &domain.Profile{Name: external.Profile}
+		if err := s.profileRepository.GetProfile(ctx, profile); err != nil {
+			return nil, err
+		}
+
+		user.Auth.ProfileID, user.Auth.Profile, changed = profile.ID, profile, true
+	}
+
+	// Users created without a password have no token yet, which their session tokens need.
+	if user.Auth.Token == nil {
+		user.Auth.Token, changed = packhub.Pointer(uuid.New().String()), true
+	}
+
+	if changed {
+		if err := s.repository.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// linkedUser returns the user an unknown external user is linked to: the one with its verified email or a new one.
+// Users with the email are only linked by it when they can't log in otherwise, having neither a password nor
+// another identity; the others must link the identity themselves, while logged in.
+func (s *federation) linkedUser(ctx context.Context, external *domain.ExternalUser, linkByEmail, provision bool, defaultProfile string) (*domain.User, error) {
+	if external.Email != "" {
+		user := &domain.User{Email: external.Email}
+		err := s.repository.GetUser(ctx, user)
+		if err == nil {
+			if !linkByEmail || !external.EmailVerified || user.Auth.Password != nil {
+				return nil, utils.ErrIdentityNotLinked
+			}
+
+			count, err := s.identityRepository.CountIdentities(ctx, user.AuthID)
+			if err != nil {
+				return nil, err
+			}
+
+			if count > 0 {
+				return nil, utils.ErrIdentityNotLinked
+			}
+
+			return user, nil
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	profileName := external.Profile
+	if profileName == "" {
+		profileName = defaultProfile
+	}
+
+	if !provision || external.Email == "" || profileName == "" {
+		return nil, utils.ErrIdentityNotLinked
+	}
+
+	profile := &domain.Profile{Name: profileName}
+	if err := s.profileRepository.GetProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	username, err := s.availableUsername(ctx, external)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Name:     external.Name,
+		Username: username,
+		Email:    external.Email,
+		Auth: &domain.Auth{
+			Status:            true,
+			ProfileID:         profile.ID,
+			Token:             packhub.Pointer(uuid.New().String()),
+			PasswordHistory:   []string{},
+			TOTPRecoveryCodes: []string{},
+		},
+	}
+	if user.Name == "" {
+		user.Name = username
+	}
+
+	if external.EmailVerified {
+		user.VerifyEmail()
+	}
+
+	if err := s.repository.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	user = &domain.User{BaseInt: domain.BaseInt{ID: user.ID}}
+	return user, s.repository.GetUser(ctx, user)
+}
+
+// linked returns the user the external user's identity is linked to, or gorm.ErrRecordNotFound.
+func (s *federation) linked(ctx context.Context, external *domain.ExternalUser) (*domain.User, error) {
+	identity := &domain.Identity{Issuer: external.Issuer, Subject: external.Subject}
+	if err := s.identityRepository.GetIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{AuthID: identity.AuthID}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// link links the external user to the logged in user, unless it's already linked to another one.
+func (s *federation) link(ctx context.Context, external *domain.ExternalUser, user *domain.User) error {
+	identity := &domain.Identity{Issuer: external.Issuer, Subject: external.Subject}
+	switch err := s.identityRepository.GetIdentity(ctx, identity); {
+	case err == nil:
+		if identity.AuthID != user.AuthID {
+			return utils.ErrIdentityInUse
+		}
+
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		identity.AuthID, identity.Email = user.AuthID, external.Email
+		return s.identityRepository.CreateIdentity(ctx, identity)
+	default:
+		return err
+	}
+}
+
+// availableUsername derives a username from the external user, suffixed when already taken.
+func (s *federation) availableUsername(ctx context.Context, external *domain.ExternalUser) (string, error) {
+	username := external.Username
+	if username == "" {
+		username, _, _ = strings.Cut(external.Email, "@")
+	}
+
+	if username == "" {
+		username = external.Subject
+	}
+
+	candidate := username
+	for {
+		err := s.repository.GetUser(ctx, &domain.User{Username: candidate})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		candidate = username + "-" + uuid.New().String()[:8]
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/raulaguila/go-api/configs"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// Impersonate opens a short-lived session of the user with the given id on behalf of actor, returning
+// an access token carrying actor in its act claim and no refresh token. Users allowed to impersonate
+// can't be impersonated themselves, nor users holding permissions actor lacks, so it never grants more
+// than actor already has.
+func (s *authService) Impersonate(ctx context.Context, actor *domain.User, userID uint, ip, userAgent string) (*dto.AuthOutputDTO, error) {
+	user := &domain.User{BaseInt: domain.BaseInt{ID: userID}}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.profileRepository.GetProfileAncestors(ctx, user.Auth.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+	user.Auth.Profile.Inherit(*ancestors)
+
+	if user.AuthID == actor.AuthID || user.HasPermission(domain.PermUsersImpersonate) {
+		return nil, utils.ErrImpersonationNotAllowed
+	}
+
+	for _, permission := range user.Auth.Profile.EffectivePermissions {
+		if !actor.HasPermission(permission) {
+			return nil, utils.ErrImpersonationNotAllowed
+		}
+	}
+
+	if !user.Auth.Status {
+		return nil, utils.ErrDisabledUser
+	}
+
+	session := &domain.Session{
+		AuthID:      user.AuthID,
+		TokenID:     uuid.New(),
+		ExpiresAt:   packhub.Pointer(time.Now().Add(configs.ImpersonationExpiration)),
+		IP:          ip,
+		UserAgent:   domain.UserAgent(userAgent),
+		LastUsedAt:  packhub.Pointer(time.Now()),
+		ActorAuthID: &actor.AuthID,
+	}
+	if err := s.sessionRepository.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepository.CreateAuditEvent(ctx, &domain.AuditEvent{
+		Action:      domain.AuditActionImpersonate,
+		ActorAuthID: actor.AuthID,
+		AuthID:      &user.AuthID,
+		SessionID:   &session.ID,
+		IP:          ip,
+		UserAgent:   domain.UserAgent(userAgent),
+	}); err != nil {
+		return nil, err
+	}
+	log.Printf("user %s impersonating user %s, session %s", actor.Subject(), user.Subject(), session.ID)
+
+	accessToken, err := user.GenerateImpersonationToken(session, actor, configs.ImpersonationExpiration, s.accessTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthOutputDTO{User: s.generateUserOutputDTO(user), AccessToken: accessToken}, nil
+}
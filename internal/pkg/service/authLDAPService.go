@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/configs"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/ldapauth"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// ldapAuthenticator verifies the passwords against an LDAP directory, linking or creating the local
+// user of the directory entry like OpenID Connect logins do.
+type ldapAuthenticator struct {
+	client     *ldapauth.Client
+	federation *federation
+}
+
+func (s *ldapAuthenticator) Authenticate(ctx context.Context, login, password string) (*domain.User, error) {
+	external, err := s.external(login, password)
+	if errors.Is(err, utils.ErrInvalidCredentials) && external != nil {
+		user, linkErr := s.federation.linked(ctx, external)
+		if linkErr != nil && !errors.Is(linkErr, gorm.ErrRecordNotFound) {
+			return nil, linkErr
+		}
+		return user, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.federation.user(ctx, external, configs.LDAPLinkByEmail, configs.LDAPAutoProvision, configs.LDAPDefaultProfile)
+}
+
+// Link verifies the directory credentials and links their entry to the logged in user.
+func (s *ldapAuthenticator) Link(ctx context.Context, user *domain.User, login, password string) error {
+	external, err := s.external(login, password)
+	if err != nil {
+		return err
+	}
+
+	return s.federation.link(ctx, external, user)
+}
+
+// external binds as the login's entry, returning it as an external user. A wrong password returns the
+// entry's identity too, when it was found.
+func (s *ldapAuthenticator) external(login, password string) (*domain.ExternalUser, error) {
+	entry, err := s.client.Authenticate(login, password)
+	switch {
+	case errors.Is(err, ldapauth.ErrUserNotFound):
+		return nil, gorm.ErrRecordNotFound
+	case errors.Is(err, ldapauth.ErrInvalidCredentials):
+		if entry == nil {
+			return nil, utils.ErrInvalidCredentials
+		}
+		return &domain.ExternalUser{Issuer: s.client.Issuer(), Subject: entry.DN}, utils.ErrInvalidCredentials
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", utils.ErrAuthenticatorUnavailable, err)
+	}
+
+	external := &domain.ExternalUser{
+		Issuer:   s.client.Issuer(),
+		Subject:  entry.DN,
+		Username: entry.Username,
+		Email:    entry.Email,
+		// Users may be able to change their own mail in the directory, so it is only trusted when configured so.
+		EmailVerified: configs.LDAPEmailTrusted,
+		Name:          entry.Name,
+	}
+	if external.Username == "" {
+		external.Username = login
+	}
+	external.Profile, _ = ldapauth.MatchProfile(entry.Groups, configs.LDAPProfileRules)
+
+	return external, nil
+}
+
+// LDAPLink links the directory entry of the credentials to the user. Wrong passwords count against the address,
+// like failed logins.
+func (s *authService) LDAPLink(ctx context.Context, user *domain.User, input *dto.LDAPLinkInputDTO) error {
+	if s.ldap == nil {
+		return utils.ErrLDAPDisabled
+	}
+
+	if err := s.checkLoginAllowed(nil, input.IP); err != nil {
+		return err
+	}
+
+	err := s.ldap.Link(ctx, user, input.Login, input.Password)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, utils.ErrInvalidCredentials) {
+		return s.registerFailedLogin(ctx, nil, input.IP, utils.ErrInvalidCredentials)
+	}
+
+	return err
+}
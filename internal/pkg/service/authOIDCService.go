@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+
+	"github.com/raulaguila/go-api/configs"
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+// OIDCAuthorization starts an OpenID Connect login, returning the provider URL to send the user to and
+// the state token to keep until the callback.
+func (s *authService) OIDCAuthorization(ctx context.Context, expiration bool) (string, string, error) {
+	return s.oidcAuthorization(ctx, func(nonce, verifier string) *domain.Claims {
+		return domain.NewOIDCState(nonce, verifier, expiration)
+	})
+}
+
+// OIDCLinkAuthorization starts an OpenID Connect login that links the provider's identity to the user,
+// instead of logging in, returning the provider URL and the state token like OIDCAuthorization.
+func (s *authService) OIDCLinkAuthorization(ctx context.Context, user *domain.User) (string, string, error) {
+	return s.oidcAuthorization(ctx, user.NewOIDCLinkState)
+}
+
+func (s *authService) oidcAuthorization(ctx context.Context, newState func(nonce, verifier string) *domain.Claims) (string, string, error) {
+	if configs.OIDCProvider == nil {
+		return "", "", utils.ErrOIDCDisabled
+	}
+
+	nonce, err := oidc.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	verifier, err := oidc.RandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	state := newState(nonce, verifier)
+	stateToken, err := s.oidcStateTokens.Sign(state)
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := configs.OIDCProvider.AuthCodeURL(ctx, state.ID, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, stateToken, nil
+}
+
+// OIDCLogin completes an OpenID Connect login, exchanging the code for the id token and logging in its user.
+func (s *authService) OIDCLogin(ctx context.Context, input *dto.OIDCCallbackInputDTO) (*dto.AuthOutputDTO, error) {
+	state, external, err := s.oidcExchange(ctx, input, false)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.federation.user(ctx, external, configs.OIDCLinkByEmail, configs.OIDCAutoProvision, configs.OIDCDefaultProfile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.Auth.Status {
+		return nil, utils.ErrDisabledUser
+	}
+
+	expiration := packhub.PointerValue(state.Expiration, true)
+	if user.Auth.TOTPEnabled {
+		mfaToken, err := user.GenerateTwoFactorToken(expiration, s.twoFactorTokens)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.AuthOutputDTO{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	return s.openSession(ctx, user, expiration, input.IP, input.UserAgent)
+}
+
+// OIDCLink completes an OpenID Connect login started by OIDCLinkAuthorization, linking the id token's
+// identity to the user the state was issued for.
+func (s *authService) OIDCLink(ctx context.Context, input *dto.OIDCCallbackInputDTO) error {
+	state, external, err := s.oidcExchange(ctx, input, true)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repository.GetUserByToken(ctx, state.Token)
+	if err != nil || user.Subject() != state.Subject || !user.Auth.Status {
+		return utils.ErrInvalidToken
+	}
+
+	return s.federation.link(ctx, external, user)
+}
+
+// oidcExchange verifies the callback's state, which must be a link state or not as asked, and exchanges
+// its code for the id token, returning its user.
+func (s *authService) oidcExchange(ctx context.Context, input *dto.OIDCCallbackInputDTO, link bool) (*domain.Claims, *domain.ExternalUser, error) {
+	if configs.OIDCProvider == nil {
+		return nil, nil, utils.ErrOIDCDisabled
+	}
+
+	state, err := s.oidcStateTokens.Parse(input.StateToken, domain.TokenTypeOIDCState)
+	if err != nil || input.State == "" || state.ID != input.State || (state.Subject != "") != link {
+		return nil, nil, utils.ErrInvalidToken
+	}
+
+	idToken, err := configs.OIDCProvider.Exchange(ctx, input.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	external := &domain.ExternalUser{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Username:      idToken.PreferredUsername,
+		Email:         idToken.Email,
+		EmailVerified: idToken.EmailVerified,
+		Name:          idToken.Name,
+	}
+	external.Profile, _ = oidc.MatchProfile(idToken.Claims, configs.OIDCProfileRules)
+
+	return state, external, nil
+}
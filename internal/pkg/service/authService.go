@@ -2,22 +2,67 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log"
+	"os"
 	"time"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
 	"github.com/raulaguila/go-api/configs"
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/totp"
+	"github.com/raulaguila/go-api/pkg/ttlmap"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
-func NewAuthService(r domain.UserRepository) domain.AuthService {
-	return &authService{
-		repository: r,
+func NewAuthService(r domain.UserRepository, sr domain.SessionRepository, ir domain.IdentityRepository, pr domain.ProfileRepository, ar domain.AuditRepository, accessTokens, refreshTokens, twoFactorTokens, oidcStateTokens *domain.TokenIssuer) domain.AuthService {
+	s := &authService{
+		repository:        r,
+		sessionRepository: sr,
+		profileRepository: pr,
+		auditRepository:   ar,
+		federation:        &federation{repository: r, identityRepository: ir, profileRepository: pr},
+		accessTokens:      accessTokens,
+		refreshTokens:     refreshTokens,
+		twoFactorTokens:   twoFactorTokens,
+		oidcStateTokens:   oidcStateTokens,
+		loginAttempts:     ttlmap.New(time.Minute),
+	}
+
+	if configs.LDAPClient != nil {
+		s.ldap = &ldapAuthenticator{client: configs.LDAPClient, federation: s.federation}
+		s.authenticators = append(s.authenticators, s.ldap)
 	}
+	s.authenticators = append(s.authenticators, &localAuthenticator{repository: r})
+
+	return s
 }
 
 type authService struct {
-	repository domain.UserRepository
+	repository        domain.UserRepository
+	sessionRepository domain.SessionRepository
+	profileRepository domain.ProfileRepository
+	auditRepository   domain.AuditRepository
+	federation        *federation
+
+	// authenticators verify the login passwords, in order. The local one comes last, as a fallback.
+	authenticators []domain.Authenticator
+	ldap           *ldapAuthenticator
+
+	// twoFactorTokens and oidcStateTokens sign the two-factor challenge and OpenID Connect state tokens, with
+	// the access keys but audiences of their own.
+	accessTokens    *domain.TokenIssuer
+	refreshTokens   *domain.TokenIssuer
+	twoFactorTokens *domain.TokenIssuer
+	oidcStateTokens *domain.TokenIssuer
+
+	// loginAttempts counts failed logins per IP address. It lives in memory, so each prefork process
+	// keeps its own counters.
+	loginAttempts *ttlmap.TTLMap
 }
 
 func (s *authService) generateUserOutputDTO(user *domain.User) *dto.UserOutputDTO {
@@ -25,62 +70,387 @@ func (s *authService) generateUserOutputDTO(user *domain.User) *dto.UserOutputDT
 		return nil
 	}
 
-	return &dto.UserOutputDTO{
-		ID:       &user.ID,
-		Name:     &user.Name,
-		Username: &user.Username,
-		Email:    &user.Email,
-		Status:   &user.Auth.Status,
+	output := &dto.UserOutputDTO{
+		ID:        &user.ID,
+		Name:      &user.Name,
+		Username:  &user.Username,
+		Email:     &user.Email,
+		Status:    &user.Auth.Status,
+		TwoFactor: &user.Auth.TOTPEnabled,
 		Profile: &dto.ProfileOutputDTO{
 			ID:          &user.Auth.Profile.ID,
 			Name:        &user.Auth.Profile.Name,
 			Permissions: &user.Auth.Profile.Permissions,
+			ParentID:    user.Auth.Profile.ParentID,
 		},
 	}
+
+	if user.Auth.Profile.EffectivePermissions != nil {
+		output.Profile.EffectivePermissions = &user.Auth.Profile.EffectivePermissions
+	}
+
+	return output
 }
 
-func (s *authService) generateAuthOutputDTO(user *domain.User, expiration bool) *dto.AuthOutputDTO {
-	accessToken, _ := user.GenerateToken(func() *time.Duration {
+func (s *authService) generateAuthOutputDTO(user *domain.User, session *domain.Session, expiration bool) (*dto.AuthOutputDTO, error) {
+	accessToken, err := user.GenerateToken(session, domain.TokenTypeAccess, func() *time.Duration {
 		if expiration {
 			return &configs.AccessExpiration
 		}
 		return nil
-	}(), configs.AccessPrivateKey)
-	refreshToken, _ := user.GenerateToken(func() *time.Duration {
+	}(), s.accessTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := user.GenerateToken(session, domain.TokenTypeRefresh, func() *time.Duration {
 		if expiration {
 			return &configs.RefreshExpiration
 		}
 		return nil
-	}(), configs.RefreshPrivateKey)
+	}(), s.refreshTokens)
+	if err != nil {
+		return nil, err
+	}
 
 	return &dto.AuthOutputDTO{
 		User:         s.generateUserOutputDTO(user),
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *authService) sessionExpiration(expiration bool) *time.Time {
+	if !expiration {
+		return nil
 	}
+
+	return packhub.Pointer(time.Now().Add(configs.RefreshExpiration))
+}
+
+// checkLoginAllowed refuses logins from an address with too many recent failures, and for users
+// backing off after a failure or locked.
+func (s *authService) checkLoginAllowed(user *domain.User, ip string) error {
+	if count, _ := s.loginAttempts.Get(ip).(int); count >= configs.LoginIPMaxAttempts {
+		return utils.ErrLoginThrottled
+	}
+
+	if user == nil || !user.Auth.Locked() {
+		return nil
+	}
+
+	if user.Auth.FailedLogins >= configs.LoginMaxAttempts {
+		return utils.ErrLockedUser
+	}
+
+	return utils.ErrLoginThrottled
+}
+
+// registerFailedLogin counts the failure against the address and, when known, the user, returning cause.
+func (s *authService) registerFailedLogin(ctx context.Context, user *domain.User, ip string, cause error) error {
+	s.loginAttempts.Increment(ip, configs.LoginIPExpiration)
+	if user == nil {
+		return cause
+	}
+
+	if err := s.repository.RegisterFailedLogin(ctx, user.Auth, configs.LoginMaxAttempts, configs.LoginLockExpiration); err != nil {
+		return err
+	}
+
+	return cause
 }
 
 func (s *authService) Login(ctx context.Context, credentials *dto.AuthInputDTO) (*dto.AuthOutputDTO, error) {
+	if err := s.checkLoginAllowed(nil, credentials.IP); err != nil {
+		return nil, err
+	}
+
+	// The local user, when there is one, is checked before its password, so locked users aren't verified
+	// at all, and also counts the failures of the other authenticators.
 	user := &domain.User{Username: credentials.Login}
-	if err := s.repository.GetUser(ctx, user); err != nil {
+	if err := s.repository.GetUser(ctx, user); errors.Is(err, gorm.ErrRecordNotFound) {
+		user = nil
+	} else if err != nil {
 		return nil, err
 	}
 
-	if !user.ValidatePassword(credentials.Password) {
-		return nil, utils.ErrInvalidCredentials
+	if err := s.checkLoginAllowed(user, credentials.IP); err != nil {
+		return nil, err
 	}
 
-	if !user.Auth.Status || user.Auth.Password == nil {
+	// The authenticator knowing the login may link it to another user than the local one, as a directory entry
+	// does, whose lock and failures count then.
+	authenticated, err := s.authenticate(ctx, credentials.Login, credentials.Password)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, utils.ErrInvalidCredentials) {
+		if authenticated != nil {
+			user = authenticated
+		}
+		return nil, s.registerFailedLogin(ctx, user, credentials.IP, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLoginAllowed(authenticated, credentials.IP); err != nil {
+		return nil, err
+	}
+
+	user = authenticated
+	if !user.Auth.Status {
 		return nil, utils.ErrDisabledUser
 	}
 
-	return s.generateAuthOutputDTO(user, credentials.Expiration), nil
+	// With two-factor authentication the failures are only cleared once the code is verified,
+	// otherwise knowing the password would allow guessing codes forever.
+	if user.Auth.TOTPEnabled {
+		mfaToken, err := user.GenerateTwoFactorToken(credentials.Expiration, s.twoFactorTokens)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.AuthOutputDTO{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	if user.Auth.FailedLogins > 0 {
+		user.Auth.ResetFailedLogins()
+		if err := s.repository.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.openSession(ctx, user, credentials.Expiration, credentials.IP, credentials.UserAgent)
+}
+
+// authenticate tries the authenticators in order until one knows the login. An unavailable one is
+// skipped, so local accounts keep working while a directory is down.
+func (s *authService) authenticate(ctx context.Context, login, password string) (*domain.User, error) {
+	err := gorm.ErrRecordNotFound
+	for _, authenticator := range s.authenticators {
+		var user *domain.User
+		if user, err = authenticator.Authenticate(ctx, login, password); err == nil {
+			return user, nil
+		}
+
+		if errors.Is(err, utils.ErrAuthenticatorUnavailable) {
+			log.Println(err)
+			continue
+		}
+
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, err
+		}
+	}
+
+	return nil, err
+}
+
+// localAuthenticator verifies the passwords hashed in the database.
+type localAuthenticator struct {
+	repository domain.UserRepository
+}
+
+func (s *localAuthenticator) Authenticate(ctx context.Context, login, password string) (*domain.User, error) {
+	user := &domain.User{Username: login}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if !user.ValidatePassword(password, configs.PasswordHasher) {
+		return user, utils.ErrInvalidCredentials
+	}
+
+	// The password is only known here, so it's the moment to upgrade a hash made with outdated parameters.
+	if rehashed, err := user.RehashPassword(password, configs.PasswordHasher); err != nil {
+		return nil, err
+	} else if rehashed {
+		if err := s.repository.UpdateUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (s *authService) openSession(ctx context.Context, user *domain.User, expiration bool, ip, userAgent string) (*dto.AuthOutputDTO, error) {
+	session := &domain.Session{
+		AuthID:     user.AuthID,
+		TokenID:    uuid.New(),
+		ExpiresAt:  s.sessionExpiration(expiration),
+		IP:         ip,
+		UserAgent:  domain.UserAgent(userAgent),
+		LastUsedAt: packhub.Pointer(time.Now()),
+	}
+	if err := s.sessionRepository.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.generateAuthOutputDTO(user, session, expiration)
 }
 
 func (s *authService) Me(user *domain.User) *dto.UserOutputDTO {
 	return s.generateUserOutputDTO(user)
 }
 
-func (s *authService) Refresh(user *domain.User, expiration bool) *dto.AuthOutputDTO {
-	return s.generateAuthOutputDTO(user, expiration)
+func (s *authService) Refresh(ctx context.Context, user *domain.User, session *domain.Session, tokenID string, expiration bool) (*dto.AuthOutputDTO, error) {
+	previous, err := uuid.Parse(tokenID)
+	if err != nil || session == nil || session.Impersonated() {
+		return nil, utils.ErrInvalidToken
+	}
+
+	session.TokenID = uuid.New()
+	session.ExpiresAt = s.sessionExpiration(expiration)
+	if err := s.sessionRepository.RotateSession(ctx, session, previous); err != nil {
+		if errors.Is(err, utils.ErrTokenReused) {
+			// An already rotated refresh token was presented again, so it may have leaked:
+			// revoke the whole family, logging out both the legitimate client and the attacker.
+			if err := s.sessionRepository.RevokeSession(ctx, session.ID); err != nil {
+				log.Println(err)
+			}
+		}
+		return nil, err
+	}
+
+	return s.generateAuthOutputDTO(user, session, expiration)
+}
+
+func (s *authService) Logout(ctx context.Context, session *domain.Session) error {
+	if session == nil {
+		return utils.ErrInvalidToken
+	}
+
+	return s.sessionRepository.RevokeSession(ctx, session.ID)
+}
+
+func (s *authService) LogoutAll(ctx context.Context, user *domain.User) error {
+	return s.sessionRepository.RevokeSessions(ctx, user.AuthID)
+}
+
+func (s *authService) GetSessions(ctx context.Context, user *domain.User, current *domain.Session) ([]dto.SessionOutputDTO, error) {
+	sessions, err := s.sessionRepository.GetSessions(ctx, user.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	outputSessions := make([]dto.SessionOutputDTO, len(*sessions))
+	for i, session := range *sessions {
+		outputSessions[i] = dto.SessionOutputDTO{
+			ID:           session.ID.String(),
+			IP:           session.IP,
+			UserAgent:    session.UserAgent,
+			CreatedAt:    session.CreatedAt,
+			LastUsedAt:   session.LastUsedAt,
+			ExpiresAt:    session.ExpiresAt,
+			Current:      current != nil && current.ID == session.ID,
+			Impersonated: session.Impersonated(),
+		}
+	}
+
+	return outputSessions, nil
+}
+
+func (s *authService) RevokeSession(ctx context.Context, user *domain.User, sessionID string) error {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return utils.ErrInvalidID
+	}
+
+	session := &domain.Session{BaseUUID: domain.BaseUUID{ID: id}, AuthID: user.AuthID}
+	if err := s.sessionRepository.GetSession(ctx, session); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.RevokeSession(ctx, session.ID)
+}
+
+func (s *authService) EnrollTwoFactor(ctx context.Context, user *domain.User) (*dto.TwoFactorEnrollOutputDTO, error) {
+	if user.Auth.TOTPEnabled {
+		return nil, utils.ErrTwoFactorEnabled
+	}
+
+	totpSecret, err := user.EnrollTwoFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorEnrollOutputDTO{
+		Secret: totpSecret,
+		URI:    totp.URI(os.Getenv("TOTP_ISSUER"), user.Username, totpSecret),
+	}, nil
+}
+
+func (s *authService) ConfirmTwoFactor(ctx context.Context, user *domain.User, code string) (*dto.RecoveryCodesOutputDTO, error) {
+	if user.Auth.TOTPEnabled {
+		return nil, utils.ErrTwoFactorEnabled
+	}
+
+	if user.Auth.TOTPSecret == nil {
+		return nil, utils.ErrTwoFactorDisabled
+	}
+
+	if !user.ValidateTwoFactorCode(code) {
+		return nil, utils.ErrInvalidTwoFactorCode
+	}
+
+	recoveryCodes, err := user.EnableTwoFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesOutputDTO{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *authService) DisableTwoFactor(ctx context.Context, user *domain.User, code string) error {
+	if !user.Auth.TOTPEnabled {
+		return utils.ErrTwoFactorDisabled
+	}
+
+	if !user.ValidateTwoFactorCode(code) {
+		return utils.ErrInvalidTwoFactorCode
+	}
+
+	user.ResetTwoFactor()
+	return s.repository.UpdateUser(ctx, user)
+}
+
+func (s *authService) VerifyTwoFactor(ctx context.Context, input *dto.TwoFactorVerifyInputDTO) (*dto.AuthOutputDTO, error) {
+	claims, err := s.twoFactorTokens.Parse(packhub.PointerValue(input.Token, ""), domain.TwoFactorChallengeType)
+	if err != nil || claims.ExpiresAt == nil {
+		return nil, utils.ErrInvalidToken
+	}
+
+	user, err := s.repository.GetUserByToken(ctx, claims.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Subject() != claims.Subject {
+		return nil, utils.ErrInvalidToken
+	}
+
+	if !user.Auth.Status || !user.Auth.TOTPEnabled {
+		return nil, utils.ErrDisabledUser
+	}
+
+	if err := s.checkLoginAllowed(user, input.IP); err != nil {
+		return nil, err
+	}
+
+	if !user.ValidateTwoFactorCode(packhub.PointerValue(input.Code, "")) {
+		return nil, s.registerFailedLogin(ctx, user, input.IP, utils.ErrInvalidTwoFactorCode)
+	}
+
+	user.Auth.ResetFailedLogins()
+	if err := s.repository.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return s.openSession(ctx, user, packhub.PointerValue(claims.Expiration, false), input.IP, input.UserAgent)
 }
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/pgfilter"
+)
+
+// generatePaginationDTO describes the page of items read with the filter. The totals are only set when
+// counted, which pages read by number always are, and the current page only when not reading by cursor.
+func generatePaginationDTO(filter *pgfilter.Filter, items int, count *int64, cursors *pgfilter.Cursors) dto.PaginationDTO {
+	pagination := dto.PaginationDTO{
+		PageSize:   uint(packhub.Max(filter.Limit, items)),
+		NextCursor: cursors.Next,
+		PrevCursor: cursors.Prev,
+	}
+
+	if filter.After == "" && filter.Before == "" {
+		pagination.CurrentPage = uint(packhub.Max(filter.Page, 1))
+	}
+
+	if count != nil {
+		pagination.TotalItems = packhub.Pointer(uint(*count))
+		pagination.TotalPages = packhub.Pointer(uint(filter.CalcPages(*count)))
+	}
+
+	return pagination
+}
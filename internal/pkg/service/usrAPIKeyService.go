@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/raulaguila/go-api/internal/pkg/domain"
+	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/utils"
+)
+
+func NewAPIKeyService(r domain.APIKeyRepository) domain.APIKeyService {
+	return &apiKeyService{
+		repository: r,
+	}
+}
+
+type apiKeyService struct {
+	repository domain.APIKeyRepository
+}
+
+func (s *apiKeyService) generateAPIKeyOutputDTO(apiKey *domain.APIKey) *dto.APIKeyOutputDTO {
+	return &dto.APIKeyOutputDTO{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Permissions: apiKey.Permissions,
+		CreatedAt:   apiKey.CreatedAt,
+		ExpiresAt:   apiKey.ExpiresAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+	}
+}
+
+func (s *apiKeyService) GetAPIKeys(ctx context.Context, user *domain.User) ([]dto.APIKeyOutputDTO, error) {
+	apiKeys, err := s.repository.GetAPIKeys(ctx, user.AuthID)
+	if err != nil {
+		return nil, err
+	}
+
+	outputAPIKeys := make([]dto.APIKeyOutputDTO, len(*apiKeys))
+	for i, apiKey := range *apiKeys {
+		outputAPIKeys[i] = *s.generateAPIKeyOutputDTO(&apiKey)
+	}
+
+	return outputAPIKeys, nil
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, user *domain.User, input *dto.APIKeyInputDTO) (*dto.APIKeyOutputDTO, error) {
+	apiKey := &domain.APIKey{
+		AuthID:      user.AuthID,
+		Name:        strings.TrimSpace(packhub.PointerValue(input.Name, "")),
+		Permissions: packhub.PointerValue(input.Permissions, pq.StringArray{}),
+		ExpiresAt:   input.ExpiresAt,
+	}
+
+	if apiKey.Permissions == nil {
+		apiKey.Permissions = pq.StringArray{}
+	}
+
+	if apiKey.Name == "" || (apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(time.Now())) {
+		return nil, utils.ErrInvalidAPIKey
+	}
+
+	// A key can only narrow down what its owner is allowed to do.
+	for _, permission := range apiKey.Permissions {
+		if !user.HasPermission(permission) {
+			return nil, utils.ErrPermissionNotGranted
+		}
+	}
+
+	key, err := apiKey.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	output := s.generateAPIKeyOutputDTO(apiKey)
+	output.Key = key
+	return output, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, user *domain.User, id uint) error {
+	return s.repository.RevokeAPIKey(ctx, &domain.APIKey{BaseInt: domain.BaseInt{ID: id}, AuthID: user.AuthID})
+}
@@ -3,11 +3,8 @@ package service
 import (
 	"context"
 
-	"github.com/lib/pq"
-
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
-	"github.com/raulaguila/go-api/pkg/packhub"
 )
 
 func NewProfileService(r domain.ProfileRepository) domain.ProfileService {
@@ -21,22 +18,37 @@ type profileService struct {
 }
 
 func (s *profileService) GenerateProfileOutputDTO(profile *domain.Profile) *dto.ProfileOutputDTO {
-	return &dto.ProfileOutputDTO{
-		ID:   &profile.ID,
-		Name: &profile.Name,
-		Permissions: func() *pq.StringArray {
-			if profile.Permissions != nil {
-				return &profile.Permissions
-			}
-			return nil
-		}(),
-	}
-
-	//if profile.Permissions != nil {
-	//	result.Permissions = &profile.Permissions
-	//}
-	//
-	//return result
+	output := &dto.ProfileOutputDTO{
+		ID:       &profile.ID,
+		Name:     &profile.Name,
+		ParentID: profile.ParentID,
+	}
+
+	if profile.Permissions != nil {
+		output.Permissions = &profile.Permissions
+	}
+
+	if profile.EffectivePermissions != nil {
+		output.EffectivePermissions = &profile.EffectivePermissions
+	}
+
+	return output
+}
+
+// GetPermissions lists the permission catalog. Labels are locale keys, left for the caller to translate.
+func (s *profileService) GetPermissions(_ context.Context) []dto.PermissionOutputDTO {
+	catalog := domain.PermissionCatalog()
+	permissions := make([]dto.PermissionOutputDTO, len(catalog))
+	for i, permission := range catalog {
+		permissions[i] = dto.PermissionOutputDTO{
+			Name:        permission.Name,
+			Label:       permission.Label,
+			Description: permission.Description,
+			Group:       permission.Group,
+		}
+	}
+
+	return permissions
 }
 
 func (s *profileService) GetProfileByID(ctx context.Context, profileID uint) (*dto.ProfileOutputDTO, error) {
@@ -45,18 +57,40 @@ func (s *profileService) GetProfileByID(ctx context.Context, profileID uint) (*d
 		return nil, err
 	}
 
+	ancestors, err := s.repository.GetProfileAncestors(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Inherit(*ancestors)
+
 	return s.GenerateProfileOutputDTO(profile), nil
 }
 
 func (s *profileService) GetProfiles(ctx context.Context, profileFilter *dto.ProfileFilter) (*dto.ItemsOutputDTO[dto.ProfileOutputDTO], error) {
-	profiles, err := s.repository.GetProfiles(ctx, profileFilter)
+	profiles, cursors, err := s.repository.GetProfiles(ctx, profileFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	count, err := s.repository.CountProfiles(ctx, profileFilter)
-	if err != nil {
-		return nil, err
+	var count *int64
+	if profileFilter.Counted() {
+		total, err := s.repository.CountProfiles(ctx, profileFilter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
+	}
+
+	// Profiles are few, so all of them are loaded once to resolve the effective permissions of the page.
+	if profileFilter.WithPermissions == nil || *profileFilter.WithPermissions {
+		all, _, err := s.repository.GetProfiles(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range *profiles {
+			(*profiles)[i].Inherit(*all)
+		}
 	}
 
 	outputProfiles := make([]dto.ProfileOutputDTO, len(*profiles))
@@ -65,13 +99,8 @@ func (s *profileService) GetProfiles(ctx context.Context, profileFilter *dto.Pro
 	}
 
 	return &dto.ItemsOutputDTO[dto.ProfileOutputDTO]{
-		Items: outputProfiles,
-		Pagination: dto.PaginationDTO{
-			CurrentPage: uint(packhub.Max(profileFilter.Page, 1)),
-			PageSize:    uint(packhub.Max(profileFilter.Limit, len(outputProfiles))),
-			TotalItems:  uint(count),
-			TotalPages:  uint(profileFilter.CalcPages(count)),
-		},
+		Items:      outputProfiles,
+		Pagination: generatePaginationDTO(&profileFilter.Filter, len(outputProfiles), count, cursors),
 	}, nil
 }
 
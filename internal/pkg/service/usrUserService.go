@@ -2,31 +2,56 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/raulaguila/go-api/configs"
 	"github.com/raulaguila/go-api/internal/pkg/domain"
 	"github.com/raulaguila/go-api/internal/pkg/dto"
+	"github.com/raulaguila/go-api/pkg/mailer"
 	"github.com/raulaguila/go-api/pkg/packhub"
+	"github.com/raulaguila/go-api/pkg/secret"
 	"github.com/raulaguila/go-api/pkg/utils"
 )
 
-func NewUserService(r domain.UserRepository) domain.UserService {
+func NewUserService(r domain.UserRepository, sr domain.SessionRepository, pr domain.PasswordResetRepository, invitationTokens *domain.TokenIssuer, ms mailer.Sender) domain.UserService {
 	return &userService{
-		repository: r,
+		repository:              r,
+		sessionRepository:       sr,
+		passwordResetRepository: pr,
+		invitationTokens:        invitationTokens,
+		mailSender:              ms,
 	}
 }
 
 type userService struct {
-	repository domain.UserRepository
+	repository              domain.UserRepository
+	sessionRepository       domain.SessionRepository
+	passwordResetRepository domain.PasswordResetRepository
+	invitationTokens        *domain.TokenIssuer
+	mailSender              mailer.Sender
 }
 
 func (s *userService) GenerateUserOutputDTO(user *domain.User) *dto.UserOutputDTO {
 	return &dto.UserOutputDTO{
-		ID:       &user.ID,
-		Name:     &user.Name,
-		Username: &user.Username,
-		Email:    &user.Email,
-		Status:   &user.Auth.Status,
-		New:      packhub.Pointer(user.Auth.Password == nil),
+		ID:            &user.ID,
+		Name:          &user.Name,
+		Username:      &user.Username,
+		Email:         &user.Email,
+		EmailVerified: packhub.Pointer(user.EmailVerifiedAt != nil),
+		Status:        &user.Auth.Status,
+		New:           packhub.Pointer(user.Auth.Password == nil),
+		TwoFactor:     &user.Auth.TOTPEnabled,
+		Locked:        packhub.Pointer(user.Auth.Locked()),
 		Profile: &dto.ProfileOutputDTO{
 			ID:   &user.Auth.Profile.ID,
 			Name: &user.Auth.Profile.Name,
@@ -44,14 +69,18 @@ func (s *userService) GetUserByID(ctx context.Context, userID uint) (*dto.UserOu
 }
 
 func (s *userService) GetUsers(ctx context.Context, userFilter *dto.UserFilter) (*dto.ItemsOutputDTO[dto.UserOutputDTO], error) {
-	users, err := s.repository.GetUsers(ctx, userFilter)
+	users, cursors, err := s.repository.GetUsers(ctx, userFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	count, err := s.repository.CountUsers(ctx, userFilter)
-	if err != nil {
-		return nil, err
+	var count *int64
+	if userFilter.Counted() {
+		total, err := s.repository.CountUsers(ctx, userFilter)
+		if err != nil {
+			return nil, err
+		}
+		count = &total
 	}
 
 	outputUsers := make([]dto.UserOutputDTO, 0)
@@ -60,22 +89,19 @@ func (s *userService) GetUsers(ctx context.Context, userFilter *dto.UserFilter)
 	}
 
 	return &dto.ItemsOutputDTO[dto.UserOutputDTO]{
-		Items: outputUsers,
-		Pagination: dto.PaginationDTO{
-			CurrentPage: uint(packhub.Max(userFilter.Page, 1)),
-			PageSize:    uint(packhub.Max(userFilter.Limit, len(outputUsers))),
-			TotalItems:  uint(count),
-			TotalPages:  uint(userFilter.CalcPages(count)),
-		},
+		Items:      outputUsers,
+		Pagination: generatePaginationDTO(&userFilter.Filter, len(outputUsers), count, cursors),
 	}, nil
 }
 
+// CreateUser creates the user without a password and mails it an invitation to set one.
 func (s *userService) CreateUser(ctx context.Context, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
-	user := &domain.User{Auth: &domain.Auth{}}
+	user := &domain.User{Auth: &domain.Auth{PasswordHistory: []string{}, TOTPRecoveryCodes: []string{}}}
 	if err := user.Bind(data); err != nil {
 		return nil, err
 	}
 
+	user.Auth.Token = packhub.Pointer(uuid.New().String())
 	if err := s.repository.CreateUser(ctx, user); err != nil {
 		return nil, err
 	}
@@ -85,7 +111,16 @@ func (s *userService) CreateUser(ctx context.Context, data *dto.UserInputDTO) (*
 		return nil, err
 	}
 
-	return s.GenerateUserOutputDTO(user), nil
+	// The user is already created, so failing to mail it doesn't fail the request, which would be retried
+	// into a conflict: it's reported in the output instead, and the user can be invited again.
+	output := s.GenerateUserOutputDTO(user)
+	output.InvitationSent = packhub.Pointer(true)
+	if err := s.sendInvitation(ctx, user); err != nil {
+		log.Printf("user %s created, but its invitation couldn't be mailed: %v", user.Subject(), err)
+		output.InvitationSent = packhub.Pointer(false)
+	}
+
+	return output, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, userID uint, data *dto.UserInputDTO) (*dto.UserOutputDTO, error) {
@@ -114,22 +149,127 @@ func (s *userService) DeleteUsers(ctx context.Context, ids []uint) error {
 	return s.repository.DeleteUsers(ctx, ids)
 }
 
-func (s *userService) ResetUserPassword(ctx context.Context, mail string) error {
+// getUserByMail avoids looking up with an empty mail, which would match the first user.
+func (s *userService) getUserByMail(ctx context.Context, mail string) (*domain.User, error) {
+	if mail == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+
 	user := &domain.User{Email: mail}
-	if err := s.repository.GetUser(ctx, user); err != nil {
+	return user, s.repository.GetUser(ctx, user)
+}
+
+// sendPasswordReset stores a new reset request for the user and mails its token.
+func (s *userService) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	token, err := secret.NewToken(32)
+	if err != nil {
+		return err
+	}
+
+	reset := &domain.PasswordReset{
+		AuthID:    user.AuthID,
+		TokenHash: secret.Hash(token),
+		ExpiresAt: time.Now().Add(configs.PasswordResetExpiration),
+	}
+	if err := s.passwordResetRepository.CreatePasswordReset(ctx, reset); err != nil {
+		return err
+	}
+
+	if link := os.Getenv("PASSWORD_RESET_URL"); link != "" {
+		token = strings.ReplaceAll(link, "{token}", url.QueryEscape(token))
+	}
+
+	return s.mailSender.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nA password reset was requested for your account. Use the token below within %v to set a new password:\n\n%s\n\nIf you did not request it, you can ignore this message.\n",
+			user.Name, configs.PasswordResetExpiration, token,
+		),
+	})
+}
+
+// ResetUserPassword logs the user out everywhere and mails a password reset token.
+// The current password keeps working until the user confirms the reset.
+func (s *userService) ResetUserPassword(ctx context.Context, mail string) error {
+	user, err := s.getUserByMail(ctx, mail)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepository.RevokeSessions(ctx, user.AuthID); err != nil {
+		return err
+	}
+
+	return s.sendPasswordReset(ctx, user)
+}
+
+func (s *userService) RequestPasswordReset(ctx context.Context, mail string) error {
+	user, err := s.getUserByMail(ctx, mail)
+	if err != nil {
+		// Don't reveal whether the mail is registered.
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
 		return err
 	}
 
-	if user.Auth.Password == nil && user.Auth.Token == nil {
+	if !user.Auth.Status {
 		return nil
 	}
 
-	user.ResetPassword()
-	return s.repository.UpdateUser(ctx, user)
+	return s.sendPasswordReset(ctx, user)
 }
 
-func (s *userService) SetUserPassword(ctx context.Context, mail string, pass *dto.PasswordInputDTO) error {
-	user := &domain.User{Email: mail}
+func (s *userService) ConfirmPasswordReset(ctx context.Context, input *dto.PasswordResetInputDTO) error {
+	reset := &domain.PasswordReset{TokenHash: secret.Hash(packhub.PointerValue(input.Token, ""))}
+	if err := s.passwordResetRepository.GetPasswordReset(ctx, reset); err != nil {
+		return err
+	}
+
+	user := &domain.User{AuthID: reset.AuthID}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		return err
+	}
+
+	// The token is only used up once the password is accepted, so a refused one can be retried with it.
+	if err := user.SetPassword(*input.Password, configs.PasswordPolicy, configs.PasswordHasher); err != nil {
+		return err
+	}
+
+	user.Auth.ResetFailedLogins()
+	user.VerifyEmail()
+	if err := s.passwordResetRepository.UsePasswordReset(ctx, reset, user); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.RevokeSessions(ctx, user.AuthID)
+}
+
+// sendInvitation mails the user a token to accept the invitation with.
+func (s *userService) sendInvitation(ctx context.Context, user *domain.User) error {
+	token, err := user.GenerateInvitationToken(configs.InvitationExpiration, s.invitationTokens)
+	if err != nil {
+		return err
+	}
+
+	if link := os.Getenv("INVITATION_URL"); link != "" {
+		token = strings.ReplaceAll(link, "{token}", url.QueryEscape(token))
+	}
+
+	return s.mailSender.Send(ctx, &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Invitation",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nAn account was created for you with the username %s. Use the token below within %v to set your password:\n\n%s\n",
+			user.Name, user.Username, configs.InvitationExpiration, token,
+		),
+	})
+}
+
+// InviteUser mails a new invitation to a user that hasn't set a password yet. Earlier invitations stop working.
+func (s *userService) InviteUser(ctx context.Context, userID uint) error {
+	user := &domain.User{BaseInt: domain.BaseInt{ID: userID}}
 	if err := s.repository.GetUser(ctx, user); err != nil {
 		return err
 	}
@@ -138,9 +278,63 @@ func (s *userService) SetUserPassword(ctx context.Context, mail string, pass *dt
 		return utils.ErrUserHasPass
 	}
 
-	if err := user.SetPassword(*pass.Password); err != nil {
+	user.Auth.Token = packhub.Pointer(uuid.New().String())
+	if err := s.repository.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	return s.sendInvitation(ctx, user)
+}
+
+// AcceptInvitation sets the invited user's first password. Receiving the invitation proves the email,
+// so it is marked verified as well.
+func (s *userService) AcceptInvitation(ctx context.Context, input *dto.InvitationInputDTO) error {
+	claims, err := s.invitationTokens.Parse(packhub.PointerValue(input.Token, ""), domain.TokenTypeInvitation)
+	if err != nil {
+		return utils.ErrInvalidInvitation
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 0)
+	if err != nil {
+		return utils.ErrInvalidInvitation
+	}
+
+	user := &domain.User{BaseInt: domain.BaseInt{ID: uint(userID)}}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.ErrInvalidInvitation
+		}
+		return err
+	}
+
+	if !user.MatchesInvitation(claims) {
+		return utils.ErrInvalidInvitation
+	}
+
+	if err := user.SetPassword(*input.Password, configs.PasswordPolicy, configs.PasswordHasher); err != nil {
+		return err
+	}
+
+	user.VerifyEmail()
+	return s.repository.UpdateUser(ctx, user)
+}
+
+func (s *userService) ResetUserTwoFactor(ctx context.Context, userID uint) error {
+	user := &domain.User{BaseInt: domain.BaseInt{ID: userID}}
+	if err := s.repository.GetUser(ctx, user); err != nil {
+		return err
+	}
+
+	user.ResetTwoFactor()
+	return s.repository.UpdateUser(ctx, user)
+}
+
+func (s *userService) UnlockUser(ctx context.Context, userID uint) error {
+	user := &domain.User{BaseInt: domain.BaseInt{ID: userID}}
+	if err := s.repository.GetUser(ctx, user); err != nil {
 		return err
 	}
 
+	user.Auth.ResetFailedLogins()
 	return s.repository.UpdateUser(ctx, user)
 }
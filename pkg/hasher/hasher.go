@@ -0,0 +1,46 @@
+package hasher
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	AlgorithmBcrypt   string = "bcrypt"
+	AlgorithmArgon2id string = "argon2id"
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+
+// PasswordHasher hashes passwords with one algorithm and set of parameters. Verify accepts hashes
+// of every supported algorithm, so stored hashes keep working after the configuration changes,
+// and NeedsRehash reports the ones that should be replaced by a new Hash.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) bool
+	NeedsRehash(hash string) bool
+}
+
+// New returns the hasher of the algorithm using its default parameters.
+func New(algorithm string) (PasswordHasher, error) {
+	switch strings.ToLower(algorithm) {
+	case AlgorithmBcrypt:
+		return NewBcrypt(0), nil
+	case AlgorithmArgon2id:
+		return NewArgon2id(0, 0, 0), nil
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+}
+
+// verify checks password against a hash of any supported algorithm.
+func verify(hash, password string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$"):
+		return verifyArgon2id(hash, password)
+	case isBcrypt(hash):
+		return verifyBcrypt(hash, password)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		expected  PasswordHasher
+		wantErr   error
+	}{
+		{"bcrypt", &Bcrypt{Cost: bcrypt.DefaultCost}, nil},
+		{"Argon2id", &Argon2id{Memory: 64 * 1024, Iterations: 3, Parallelism: 2}, nil},
+		{"md5", nil, ErrUnknownAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			h, err := New(tt.algorithm)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.expected, h)
+		})
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{"bcrypt", NewBcrypt(bcrypt.MinCost), "$2a$04$"},
+		{"argon2id", NewArgon2id(1024, 1, 1), "$argon2id$v=19$m=1024,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("secret")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			assert.True(t, tt.hasher.Verify(hash, "secret"))
+			assert.False(t, tt.hasher.Verify(hash, "Secret"))
+			assert.False(t, tt.hasher.NeedsRehash(hash))
+
+			other, err := tt.hasher.Hash("secret")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, other)
+		})
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	bcryptHasher, argon2idHasher := NewBcrypt(bcrypt.MinCost), NewArgon2id(1024, 1, 1)
+
+	bcryptHash, err := bcryptHasher.Hash("secret")
+	require.NoError(t, err)
+	argon2idHash, err := argon2idHasher.Hash("secret")
+	require.NoError(t, err)
+
+	assert.True(t, argon2idHasher.Verify(bcryptHash, "secret"))
+	assert.True(t, bcryptHasher.Verify(argon2idHash, "secret"))
+	assert.True(t, argon2idHasher.NeedsRehash(bcryptHash))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2idHash))
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcryptHash, err := NewBcrypt(bcrypt.MinCost).Hash("secret")
+	require.NoError(t, err)
+	argon2idHash, err := NewArgon2id(1024, 1, 1).Hash("secret")
+	require.NoError(t, err)
+
+	assert.True(t, NewBcrypt(bcrypt.MinCost+1).NeedsRehash(bcryptHash))
+	assert.True(t, NewArgon2id(2048, 1, 1).NeedsRehash(argon2idHash))
+	assert.True(t, NewArgon2id(1024, 2, 1).NeedsRehash(argon2idHash))
+	assert.True(t, NewArgon2id(1024, 1, 2).NeedsRehash(argon2idHash))
+}
+
+func TestVerifyInvalidHashes(t *testing.T) {
+	h := NewArgon2id(1024, 1, 1)
+	for _, hash := range []string{
+		"",
+		"secret",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+	} {
+		assert.False(t, h.Verify(hash, "secret"), hash)
+		assert.True(t, h.NeedsRehash(hash), hash)
+	}
+}
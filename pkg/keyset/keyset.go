@@ -0,0 +1,271 @@
+package keyset
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoActiveKey       = errors.New("key set without active key")
+	ErrUnknownKey        = errors.New("unknown signing key")
+	ErrUnsupportedKey    = errors.New("unsupported signing key")
+	ErrAlgorithmMismatch = errors.New("signing algorithm does not match the key")
+)
+
+type (
+	// Key is a signing key identified by the RFC 7638 thumbprint of its public key.
+	Key struct {
+		ID         string
+		Method     jwt.SigningMethod
+		PrivateKey crypto.Signer
+	}
+
+	// KeySet signs tokens with its active key and verifies them with any of its keys, so a key can be
+	// retired, keeping the tokens it signed valid until they expire, while a new one takes its place.
+	KeySet struct {
+		keys []*Key
+	}
+
+	// JWK is the public part of a key, as published in a JWKS.
+	JWK struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		Crv string `json:"crv,omitempty"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	JWKS struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+// NewKey wraps an RSA, P-256 ECDSA or Ed25519 private key, signing with RS256, ES256 or EdDSA respectively.
+// An algorithm other than the empty string must be the one of the key.
+func NewKey(privateKey crypto.Signer, algorithm string) (*Key, error) {
+	var method jwt.SigningMethod
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, ErrUnsupportedKey
+		}
+		method = jwt.SigningMethodES256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if algorithm != "" && algorithm != method.Alg() {
+		return nil, ErrAlgorithmMismatch
+	}
+
+	jwk := newJWK(privateKey.Public())
+	sum := sha256.Sum256([]byte(jwk.thumbprintInput()))
+	return &Key{ID: base64.RawURLEncoding.EncodeToString(sum[:]), Method: method, PrivateKey: privateKey}, nil
+}
+
+// ParsePEM parses a PKCS #1 RSA, SEC 1 EC or PKCS #8 private key.
+func ParsePEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrUnsupportedKey
+	}
+
+	var (
+		privateKey any
+		err        error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	return signer, nil
+}
+
+// New returns a key set signing with active and still verifying the retired keys.
+func New(active *Key, retired ...*Key) (*KeySet, error) {
+	if active == nil {
+		return nil, ErrNoActiveKey
+	}
+
+	return &KeySet{keys: append([]*Key{active}, retired...)}, nil
+}
+
+// Active returns the key new tokens are signed with.
+func (s *KeySet) Active() *Key {
+	return s.keys[0]
+}
+
+// Algorithms returns the signing algorithms of the keys, to be used as the parser's valid methods.
+func (s *KeySet) Algorithms() []string {
+	var algorithms []string
+	for _, key := range s.keys {
+		if !slices.Contains(algorithms, key.Method.Alg()) {
+			algorithms = append(algorithms, key.Method.Alg())
+		}
+	}
+
+	return algorithms
+}
+
+// Sign signs the claims with the active key, identifying it in the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.Active().Method, claims)
+	token.Header["kid"] = s.Active().ID
+	return token.SignedString(s.Active().PrivateKey)
+}
+
+// Keyfunc resolves the verification key of a token by its kid header, requiring the token to be signed
+// with the algorithm of the key. Tokens without one, signed before keys had ids, are checked against
+// every key of the set using their algorithm.
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		keys := jwt.VerificationKeySet{}
+		for _, key := range s.keys {
+			if key.Method.Alg() == token.Method.Alg() {
+				keys.Keys = append(keys.Keys, key.PrivateKey.Public())
+			}
+		}
+		if len(keys.Keys) == 0 {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return keys, nil
+	}
+
+	for _, key := range s.keys {
+		if key.ID == kid {
+			if key.Method.Alg() != token.Method.Alg() {
+				return nil, jwt.ErrTokenSignatureInvalid
+			}
+			return key.PrivateKey.Public(), nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// JWKS returns the public keys of the set.
+func (s *KeySet) JWKS() *JWKS {
+	jwks := &JWKS{Keys: make([]JWK, len(s.keys))}
+	for i, key := range s.keys {
+		jwk := newJWK(key.PrivateKey.Public())
+		jwk.Use, jwk.Alg, jwk.Kid = "sig", key.Method.Alg(), key.ID
+		jwks.Keys[i] = *jwk
+	}
+
+	return jwks
+}
+
+// PublicKey decodes the RSA, EC or Ed25519 public key of the JWK.
+func (s *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch s.Kty {
+	case "RSA":
+		n, errN := decode(s.N)
+		e, errE := decode(s.E)
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrUnsupportedKey
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch s.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ErrUnsupportedKey
+		}
+
+		x, errX := decode(s.X)
+		y, errY := decode(s.Y)
+		if errX != nil || errY != nil {
+			return nil, ErrUnsupportedKey
+		}
+
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, ErrUnsupportedKey
+		}
+		return publicKey, nil
+	case "OKP":
+		x, err := decode(s.X)
+		if err != nil || s.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func newJWK(publicKey crypto.PublicKey) *JWK {
+	switch k := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &JWK{Kty: "RSA", N: encode(k.N.Bytes()), E: encode(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JWK{Kty: "EC", Crv: k.Curve.Params().Name, X: encode(k.X.FillBytes(make([]byte, size))), Y: encode(k.Y.FillBytes(make([]byte, size)))}
+	case ed25519.PublicKey:
+		return &JWK{Kty: "OKP", Crv: "Ed25519", X: encode(k)}
+	default:
+		return &JWK{}
+	}
+}
+
+// thumbprintInput returns the required members of the JWK in lexicographic order (RFC 7638, section 3.2).
+func (s *JWK) thumbprintInput() string {
+	switch s.Kty {
+	case "RSA":
+		return `{"e":"` + s.E + `","kty":"RSA","n":"` + s.N + `"}`
+	case "EC":
+		return `{"crv":"` + s.Crv + `","kty":"EC","x":"` + s.X + `","y":"` + s.Y + `"}`
+	default:
+		return `{"crv":"` + s.Crv + `","kty":"` + s.Kty + `","x":"` + s.X + `"}`
+	}
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
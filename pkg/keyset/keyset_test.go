@@ -0,0 +1,243 @@
+package keyset
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateSigner(t *testing.T, kind string) crypto.Signer {
+	t.Helper()
+
+	var (
+		signer crypto.Signer
+		err    error
+	)
+	switch kind {
+	case "RS256":
+		signer, err = rsa.GenerateKey(rand.Reader, 1024)
+	case "ES256":
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		signer, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "EdDSA":
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	}
+	require.NoError(t, err)
+	return signer
+}
+
+func generateKey(t *testing.T, algorithm string) *Key {
+	t.Helper()
+	key, err := NewKey(generateSigner(t, algorithm), "")
+	require.NoError(t, err)
+	return key
+}
+
+func TestNewKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		signer    string
+		algorithm string
+		expected  string
+		wantErr   error
+	}{
+		{"rsa", "RS256", "", "RS256", nil},
+		{"ecdsa", "ES256", "", "ES256", nil},
+		{"ed25519", "EdDSA", "", "EdDSA", nil},
+		{"explicit algorithm", "ES256", "ES256", "ES256", nil},
+		{"algorithm mismatch", "RS256", "ES256", "", ErrAlgorithmMismatch},
+		{"unsupported curve", "ES384", "", "", ErrUnsupportedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewKey(generateSigner(t, tt.signer), tt.algorithm)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, key.Method.Alg())
+			assert.Len(t, key.ID, 43)
+		})
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	// RFC 7638, section 3.1.
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	require.NoError(t, err)
+
+	key, err := NewKey(&rsa.PrivateKey{PublicKey: rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", key.ID)
+}
+
+func TestParsePEM(t *testing.T) {
+	rsaKey := generateSigner(t, "RS256").(*rsa.PrivateKey)
+	ecKey := generateSigner(t, "ES256").(*ecdsa.PrivateKey)
+	edKey := generateSigner(t, "EdDSA")
+
+	ecBytes, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		block    *pem.Block
+		expected crypto.Signer
+	}{
+		{"pkcs1 rsa", &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}, rsaKey},
+		{"sec1 ec", &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecBytes}, ecKey},
+		{"pkcs8 ed25519", &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}, edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := ParsePEM(pem.EncodeToMemory(tt.block))
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()))
+		})
+	}
+
+	_, err = ParsePEM([]byte("not a pem"))
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+
+	_, err = ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte{}}))
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil)
+	assert.ErrorIs(t, err, ErrNoActiveKey)
+
+	active, retired := generateKey(t, "ES256"), generateKey(t, "RS256")
+	set, err := New(active, retired)
+	require.NoError(t, err)
+	assert.Same(t, active, set.Active())
+	assert.Equal(t, []string{"ES256", "RS256"}, set.Algorithms())
+}
+
+func TestSignAndKeyfunc(t *testing.T) {
+	for _, algorithm := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			oldKey, newKey, unknownKey := generateKey(t, "RS256"), generateKey(t, algorithm), generateKey(t, algorithm)
+
+			oldSet, err := New(oldKey)
+			require.NoError(t, err)
+			rotatedSet, err := New(newKey, oldKey)
+			require.NoError(t, err)
+			unknownSet, err := New(unknownKey)
+			require.NoError(t, err)
+
+			oldToken, err := oldSet.Sign(jwt.MapClaims{"sub": "1"})
+			require.NoError(t, err)
+			newToken, err := rotatedSet.Sign(jwt.MapClaims{"sub": "1"})
+			require.NoError(t, err)
+			unknownToken, err := unknownSet.Sign(jwt.MapClaims{"sub": "1"})
+			require.NoError(t, err)
+			withoutKid, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "1"}).SignedString(oldKey.PrivateKey)
+			require.NoError(t, err)
+			hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1"})
+			hmacToken.Header["kid"] = newKey.ID
+			hmacSigned, err := hmacToken.SignedString([]byte("secret"))
+			require.NoError(t, err)
+
+			tests := []struct {
+				name  string
+				token string
+				valid bool
+			}{
+				{"retired key", oldToken, true},
+				{"active key", newToken, true},
+				{"without kid", withoutKid, true},
+				{"unknown key", unknownToken, false},
+				{"other algorithm with known kid", hmacSigned, false},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					parsed, err := jwt.Parse(tt.token, rotatedSet.Keyfunc, jwt.WithValidMethods(rotatedSet.Algorithms()))
+					if tt.valid {
+						require.NoError(t, err)
+						assert.True(t, parsed.Valid)
+					} else {
+						assert.Error(t, err)
+					}
+				})
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, newKey.ID, parsed.Header["kid"])
+			assert.Equal(t, algorithm, parsed.Header["alg"])
+		})
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, ecKey, edKey := generateKey(t, "RS256"), generateKey(t, "ES256"), generateKey(t, "EdDSA")
+	set, err := New(rsaKey, ecKey, edKey)
+	require.NoError(t, err)
+
+	jwks := set.JWKS()
+	require.Len(t, jwks.Keys, 3)
+
+	rsaJWK := jwks.Keys[0]
+	assert.Equal(t, JWK{Kty: "RSA", Use: "sig", Alg: "RS256", Kid: rsaKey.ID, N: rsaJWK.N, E: "AQAB"}, rsaJWK)
+	n, err := base64.RawURLEncoding.DecodeString(rsaJWK.N)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rsaKey.PrivateKey.(*rsa.PrivateKey).N.Cmp(new(big.Int).SetBytes(n)))
+
+	ecJWK := jwks.Keys[1]
+	assert.Equal(t, "EC", ecJWK.Kty)
+	assert.Equal(t, "P-256", ecJWK.Crv)
+	assert.Equal(t, "ES256", ecJWK.Alg)
+	for _, coordinate := range []string{ecJWK.X, ecJWK.Y} {
+		decoded, err := base64.RawURLEncoding.DecodeString(coordinate)
+		require.NoError(t, err)
+		assert.Len(t, decoded, 32)
+	}
+
+	edJWK := jwks.Keys[2]
+	assert.Equal(t, JWK{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: edKey.ID, Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edKey.PrivateKey.Public().(ed25519.PublicKey))}, edJWK)
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	for _, kind := range []string{"RS256", "ES256", "ES384", "EdDSA"} {
+		t.Run(kind, func(t *testing.T) {
+			signer := generateSigner(t, kind)
+			publicKey, err := newJWK(signer.Public()).PublicKey()
+			require.NoError(t, err)
+			assert.True(t, publicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()))
+		})
+	}
+
+	for name, jwk := range map[string]JWK{
+		"unknown type":   {Kty: "oct"},
+		"unknown curve":  {Kty: "EC", Crv: "P-192"},
+		"off curve":      {Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"},
+		"invalid base64": {Kty: "RSA", N: "!", E: "AQAB"},
+		"short ed25519":  {Kty: "OKP", Crv: "Ed25519", X: "AQ"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := jwk.PublicKey()
+			assert.ErrorIs(t, err, ErrUnsupportedKey)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package ldapauth
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid ldap credentials")
+	ErrUserNotFound       = errors.New("ldap user not found")
+	ErrUnavailable        = errors.New("ldap server unavailable")
+)
+
+// LoginPlaceholder is replaced, escaped, by the login in Config.UserFilter.
+const LoginPlaceholder string = "{login}"
+
+type (
+	Config struct {
+		URL      string
+		StartTLS bool
+
+		// BindDN and BindPassword are the service account searching for users. Empty binds anonymously.
+		BindDN       string
+		BindPassword string
+
+		BaseDN     string
+		UserFilter string
+
+		UsernameAttribute string
+		EmailAttribute    string
+		NameAttribute     string
+		GroupAttribute    string
+
+		Timeout   time.Duration
+		TLSConfig *tls.Config
+	}
+
+	// Entry is an authenticated directory user. Groups holds the DNs of the groups it is a member of.
+	Entry struct {
+		DN       string
+		Username string
+		Email    string
+		Name     string
+		Groups   []string
+	}
+
+	// Client authenticates users by searching their entry with the service account and binding as it.
+	Client struct {
+		config Config
+	}
+)
+
+func New(config Config) *Client {
+	if config.UserFilter == "" {
+		config.UserFilter = "(uid=" + LoginPlaceholder + ")"
+	}
+
+	if config.UsernameAttribute == "" {
+		config.UsernameAttribute = "uid"
+	}
+
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = "mail"
+	}
+
+	if config.NameAttribute == "" {
+		config.NameAttribute = "cn"
+	}
+
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = "memberOf"
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	return &Client{config: config}
+}
+
+// Issuer identifies the directory the entries come from.
+func (s *Client) Issuer() string {
+	return "ldap:" + s.config.BaseDN
+}
+
+// Authenticate verifies the password of the user matching login. Failures to reach or search the
+// directory are reported as ErrUnavailable. A wrong password returns ErrInvalidCredentials along with
+// the entry's DN, so the failure can be counted against it.
+func (s *Client) Authenticate(login, password string) (*Entry, error) {
+	if login == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	if s.config.BindDN != "" {
+		err = conn.Bind(s.config.BindDN, s.config.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		s.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(s.config.Timeout.Seconds()), false,
+		strings.ReplaceAll(s.config.UserFilter, LoginPlaceholder, ldap.EscapeFilter(login)),
+		[]string{s.config.UsernameAttribute, s.config.EmailAttribute, s.config.NameAttribute, s.config.GroupAttribute},
+		nil,
+	))
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	// More than one match means the filter doesn't identify users, so none of them is trusted.
+	if len(result.Entries) != 1 {
+		return nil, ErrUserNotFound
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return &Entry{DN: entry.DN}, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return &Entry{
+		DN:       entry.DN,
+		Username: entry.GetAttributeValue(s.config.UsernameAttribute),
+		Email:    entry.GetAttributeValue(s.config.EmailAttribute),
+		Name:     entry.GetAttributeValue(s.config.NameAttribute),
+		Groups:   entry.GetAttributeValues(s.config.GroupAttribute),
+	}, nil
+}
+
+func (s *Client) dial() (*ldap.Conn, error) {
+	options := []ldap.DialOpt{ldap.DialWithDialer(&net.Dialer{Timeout: s.config.Timeout})}
+	if s.config.TLSConfig != nil {
+		options = append(options, ldap.DialWithTLSConfig(s.config.TLSConfig))
+	}
+
+	conn, err := ldap.DialURL(s.config.URL, options...)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(s.config.Timeout)
+
+	if s.config.StartTLS {
+		tlsConfig := s.config.TLSConfig
+		if tlsConfig == nil {
+			serverURL, err := url.Parse(s.config.URL)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			tlsConfig = &tls.Config{ServerName: serverURL.Hostname(), MinVersion: tls.VersionTLS12}
+		}
+
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
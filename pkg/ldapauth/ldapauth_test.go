@@ -0,0 +1,119 @@
+package ldapauth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/ldapauth"
+	"github.com/raulaguila/go-api/pkg/ldapauth/ldaptest"
+)
+
+const (
+	baseDN    = "dc=example,dc=com"
+	serviceDN = "cn=service,ou=system,dc=example,dc=com"
+	adminsDN  = "cn=admins,ou=groups,dc=example,dc=com"
+)
+
+func newServer(t *testing.T) *ldaptest.Server {
+	t.Helper()
+
+	server, err := ldaptest.New(
+		ldaptest.Entry{DN: serviceDN, Password: "service-secret"},
+		ldaptest.Entry{
+			DN:       "uid=john,ou=people,dc=example,dc=com",
+			Password: "john-secret",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"john"},
+				"mail":        {"john@example.com"},
+				"cn":          {"John Doe"},
+				"memberOf":    {adminsDN, "cn=users,ou=groups,dc=example,dc=com"},
+			},
+		},
+		ldaptest.Entry{
+			DN:         "uid=jane,ou=people,dc=example,dc=com",
+			Password:   "jane-secret",
+			Attributes: map[string][]string{"objectClass": {"person"}, "uid": {"jane"}, "mail": {"jane@example.com"}},
+		},
+		ldaptest.Entry{
+			DN:         "uid=jane,ou=contractors,dc=example,dc=com",
+			Password:   "other-secret",
+			Attributes: map[string][]string{"objectClass": {"person"}, "uid": {"jane"}},
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = server.Close() })
+	return server
+}
+
+func newClient(server *ldaptest.Server) *ldapauth.Client {
+	return ldapauth.New(ldapauth.Config{
+		URL:          server.URL(),
+		BindDN:       serviceDN,
+		BindPassword: "service-secret",
+		BaseDN:       baseDN,
+		UserFilter:   "(&(objectClass=person)(uid={login}))",
+		Timeout:      time.Second,
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	client := newClient(newServer(t))
+
+	entry, err := client.Authenticate("john", "john-secret")
+	require.NoError(t, err)
+	assert.Equal(t, &ldapauth.Entry{
+		DN:       "uid=john,ou=people,dc=example,dc=com",
+		Username: "john",
+		Email:    "john@example.com",
+		Name:     "John Doe",
+		Groups:   []string{adminsDN, "cn=users,ou=groups,dc=example,dc=com"},
+	}, entry)
+	assert.Equal(t, "ldap:"+baseDN, client.Issuer())
+}
+
+func TestAuthenticateFailures(t *testing.T) {
+	server := newServer(t)
+	client := newClient(server)
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+		err      error
+	}{
+		{"wrong password", "john", "wrong", ldapauth.ErrInvalidCredentials},
+		{"empty password", "john", "", ldapauth.ErrInvalidCredentials},
+		{"unknown user", "nobody", "secret", ldapauth.ErrUserNotFound},
+		{"ambiguous user", "jane", "jane-secret", ldapauth.ErrUserNotFound},
+		{"filter injection", "*", "john-secret", ldapauth.ErrUserNotFound},
+		{"filter injection with parenthesis", "john)(uid=*", "john-secret", ldapauth.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Authenticate(tt.login, tt.password)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("wrong password names the entry", func(t *testing.T) {
+		entry, err := client.Authenticate("john", "wrong")
+		assert.ErrorIs(t, err, ldapauth.ErrInvalidCredentials)
+		assert.Equal(t, &ldapauth.Entry{DN: "uid=john,ou=people,dc=example,dc=com"}, entry)
+	})
+
+	t.Run("wrong service password", func(t *testing.T) {
+		_, err := ldapauth.New(ldapauth.Config{URL: server.URL(), BindDN: serviceDN, BindPassword: "wrong", BaseDN: baseDN}).Authenticate("john", "john-secret")
+		assert.ErrorIs(t, err, ldapauth.ErrUnavailable)
+	})
+
+	t.Run("server down", func(t *testing.T) {
+		require.NoError(t, server.Close())
+		_, err := client.Authenticate("john", "john-secret")
+		assert.ErrorIs(t, err, ldapauth.ErrUnavailable)
+	})
+}
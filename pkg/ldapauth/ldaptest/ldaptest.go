@@ -0,0 +1,264 @@
+// Package ldaptest is an in-process LDAP server stub for tests. It only answers simple binds and
+// searches, evaluating and, or, not, equality and presence filters over its entries.
+package ldaptest
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+type (
+	// Entry is a directory entry. Binding as DN succeeds with Password; entries without one can't bind.
+	Entry struct {
+		DN         string
+		Password   string
+		Attributes map[string][]string
+	}
+
+	Server struct {
+		listener net.Listener
+		entries  []Entry
+		wg       sync.WaitGroup
+	}
+)
+
+// New starts a server on a local port serving the entries.
+func New(entries ...Entry) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener, entries: entries}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// URL returns the ldap:// URL the server listens on.
+func (s *Server) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// Close stops accepting connections and waits for the open ones to end.
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		messageID, _ := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = []*ber.Packet{s.bind(messageID, request)}
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(messageID, request)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = []*ber.Packet{result(messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultUnwillingToPerform, "operation not supported")}
+		}
+
+		for _, response := range responses {
+			if _, err := conn.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) bind(messageID int64, request *ber.Packet) *ber.Packet {
+	if len(request.Children) < 3 {
+		return result(messageID, ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "malformed bind")
+	}
+
+	dn, _ := request.Children[1].Value.(string)
+	password := request.Children[2].Data.String()
+	if dn == "" && password == "" {
+		return result(messageID, ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+	}
+
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			return result(messageID, ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+		}
+	}
+
+	return result(messageID, ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *Server) search(messageID int64, request *ber.Packet) []*ber.Packet {
+	if len(request.Children) < 8 {
+		return []*ber.Packet{result(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "malformed search")}
+	}
+
+	baseDN, _ := request.Children[0].Value.(string)
+	sizeLimit, _ := request.Children[3].Value.(int64)
+	filter := request.Children[6]
+
+	var attributes []string
+	for _, attribute := range request.Children[7].Children {
+		if name, ok := attribute.Value.(string); ok {
+			attributes = append(attributes, name)
+		}
+	}
+
+	var responses []*ber.Packet
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.DN), strings.ToLower(baseDN)) {
+			continue
+		}
+
+		matched, err := matches(filter, entry)
+		if err != nil {
+			return []*ber.Packet{result(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultUnwillingToPerform, err.Error())}
+		}
+
+		if !matched {
+			continue
+		}
+
+		if sizeLimit > 0 && int64(len(responses)) >= sizeLimit {
+			return append(responses, result(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSizeLimitExceeded, ""))
+		}
+
+		responses = append(responses, searchEntry(messageID, entry, attributes))
+	}
+
+	return append(responses, result(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func matches(filter *ber.Packet, entry Entry) (bool, error) {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if ok, err := matches(child, entry); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if ok, err := matches(child, entry); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case ldap.FilterNot:
+		if len(filter.Children) != 1 {
+			return false, errors.New("malformed not filter")
+		}
+		ok, err := matches(filter.Children[0], entry)
+		return !ok, err
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) != 2 {
+			return false, errors.New("malformed equality filter")
+		}
+		name, _ := filter.Children[0].Value.(string)
+		value, _ := filter.Children[1].Value.(string)
+		for _, candidate := range attribute(entry, name) {
+			if strings.EqualFold(candidate, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case ldap.FilterPresent:
+		return len(attribute(entry, filter.Data.String())) > 0, nil
+	default:
+		return false, errors.New("unsupported filter")
+	}
+}
+
+func attribute(entry Entry, name string) []string {
+	for key, values := range entry.Attributes {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+func searchEntry(messageID int64, entry Entry, attributes []string) *ber.Packet {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range entry.Attributes {
+		if len(attributes) > 0 && !containsFold(attributes, name) {
+			continue
+		}
+
+		item := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		item.AppendChild(set)
+		list.AppendChild(item)
+	}
+	response.AppendChild(list)
+
+	envelope.AppendChild(response)
+	return envelope
+}
+
+func result(messageID int64, application ber.Tag, code uint16, diagnostic string) *ber.Packet {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, application, nil, "Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, diagnostic, "Diagnostic Message"))
+
+	envelope.AppendChild(response)
+	return envelope
+}
+
+func containsFold(items []string, item string) bool {
+	for _, candidate := range items {
+		if strings.EqualFold(candidate, item) {
+			return true
+		}
+	}
+
+	return false
+}
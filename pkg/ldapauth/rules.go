@@ -0,0 +1,57 @@
+package ldapauth
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidRule = errors.New("invalid group rule")
+
+// GroupRule maps the members of the group with the DN Group to the local profile named Profile.
+type GroupRule struct {
+	Group   string
+	Profile string
+}
+
+// ParseGroupRules parses semicolon separated "group DN:profile" rules. Group DNs hold commas, so the
+// profile is whatever follows the last colon.
+func ParseGroupRules(rules string) ([]GroupRule, error) {
+	parsed := make([]GroupRule, 0)
+	for _, item := range strings.Split(rules, ";") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(item, ":")
+		if separator <= 0 || separator == len(item)-1 {
+			return nil, ErrInvalidRule
+		}
+
+		parsed = append(parsed, GroupRule{Group: strings.TrimSpace(item[:separator]), Profile: strings.TrimSpace(item[separator+1:])})
+	}
+
+	return parsed, nil
+}
+
+// MatchProfile returns the profile of the first rule whose group is one of groups. DNs are compared
+// ignoring case and the spaces after commas.
+func MatchProfile(groups []string, rules []GroupRule) (string, bool) {
+	for _, rule := range rules {
+		for _, group := range groups {
+			if normalizeDN(group) == normalizeDN(rule.Group) {
+				return rule.Profile, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	return strings.ToLower(strings.Join(parts, ","))
+}
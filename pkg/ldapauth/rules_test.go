@@ -0,0 +1,43 @@
+package ldapauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGroupRules(t *testing.T) {
+	rules, err := ParseGroupRules("cn=admins,ou=groups,dc=example,dc=com:ADMIN; cn=devs,ou=groups,dc=example,dc=com : DEV ;")
+	require.NoError(t, err)
+	assert.Equal(t, []GroupRule{
+		{Group: "cn=admins,ou=groups,dc=example,dc=com", Profile: "ADMIN"},
+		{Group: "cn=devs,ou=groups,dc=example,dc=com", Profile: "DEV"},
+	}, rules)
+
+	rules, err = ParseGroupRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{"cn=admins", ":ADMIN", "cn=admins:"} {
+		_, err := ParseGroupRules(invalid)
+		assert.ErrorIs(t, err, ErrInvalidRule, invalid)
+	}
+}
+
+func TestMatchProfile(t *testing.T) {
+	rules := []GroupRule{
+		{Group: "cn=admins,ou=groups,dc=example,dc=com", Profile: "ADMIN"},
+		{Group: "cn=devs,ou=groups,dc=example,dc=com", Profile: "DEV"},
+	}
+
+	profile, ok := MatchProfile([]string{"cn=devs,ou=groups,dc=example,dc=com", "CN=Admins, OU=Groups, DC=example, DC=com"}, rules)
+	assert.True(t, ok)
+	assert.Equal(t, "ADMIN", profile, "rules are matched in order, ignoring case and spaces")
+
+	_, ok = MatchProfile([]string{"cn=users,ou=groups,dc=example,dc=com"}, rules)
+	assert.False(t, ok)
+
+	_, ok = MatchProfile(nil, rules)
+	assert.False(t, ok)
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+func NewFileSender(dir, from string) *FileSender {
+	return &FileSender{
+		dir:  dir,
+		from: from,
+	}
+}
+
+// FileSender writes every message as an .eml file into a directory instead of delivering it.
+type FileSender struct {
+	dir     string
+	from    string
+	counter atomic.Uint64
+}
+
+func (s *FileSender) Send(_ context.Context, message *Message) error {
+	data, err := message.build(s.from)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%d.eml", time.Now().UnixNano(), s.counter.Add(1))
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o640)
+}
@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Sender delivers mail messages. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+func (m *Message) validate() error {
+	if m == nil || len(m.To) == 0 || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+
+	for _, to := range m.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+		}
+	}
+
+	return nil
+}
+
+// build renders the message as a plain text RFC 5322 mail.
+func (m *Message) build(from string) ([]byte, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(buf)
+	if _, err := writer.Write([]byte(m.Body)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *Message
+		wantErr bool
+	}{
+		{"valid", &Message{To: []string{"john@email.com"}, Subject: "Olá", Body: "Body"}, false},
+		{"nil message", nil, true},
+		{"no recipient", &Message{Subject: "Subject", Body: "Body"}, true},
+		{"invalid recipient", &Message{To: []string{"john"}, Subject: "Subject"}, true},
+		{"header injection", &Message{To: []string{"john@email.com"}, Subject: "Subject\r\nBcc: evil@email.com"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.message.build("api@email.com")
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMessage)
+				return
+			}
+			require.NoError(t, err)
+
+			parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+			require.NoError(t, err)
+			assert.Equal(t, "api@email.com", parsed.Header.Get("From"))
+			assert.Equal(t, strings.Join(tt.message.To, ", "), parsed.Header.Get("To"))
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.message.Subject, subject)
+		})
+	}
+}
+
+func TestMemorySender(t *testing.T) {
+	sender := NewMemorySender()
+	require.NoError(t, sender.Send(context.Background(), &Message{To: []string{"john@email.com"}, Subject: "First"}))
+	require.NoError(t, sender.Send(context.Background(), &Message{To: []string{"john@email.com"}, Subject: "Second"}))
+	require.Error(t, sender.Send(context.Background(), &Message{Subject: "Invalid"}))
+
+	messages := sender.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "First", messages[0].Subject)
+	assert.Equal(t, "Second", messages[1].Subject)
+}
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	sender := NewFileSender(dir, "api@email.com")
+	require.NoError(t, sender.Send(context.Background(), &Message{To: []string{"john@email.com"}, Subject: "Subject", Body: "Body"}))
+	require.NoError(t, sender.Send(context.Background(), &Message{To: []string{"john@email.com"}, Subject: "Subject", Body: "Body"}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: john@email.com")
+}
+
+func TestSMTPSender(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go serveSMTP(t, listener, received)
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	sender := NewSMTPSender(host, port, "", "", "api@email.com")
+	require.NoError(t, sender.Send(context.Background(), &Message{To: []string{"john@email.com"}, Subject: "Subject", Body: "Body"}))
+	assert.Contains(t, <-received, "Subject: Subject")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sender.Send(ctx, &Message{To: []string{"john@email.com"}}), context.Canceled)
+}
+
+// serveSMTP answers a single SMTP conversation, enough for net/smtp.SendMail without TLS or auth.
+func serveSMTP(t *testing.T, listener net.Listener, received chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	write := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	write("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			write("250 localhost")
+		case strings.HasPrefix(command, "DATA"):
+			write("354 end data with <CR><LF>.<CR><LF>")
+			data := new(strings.Builder)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			received <- data.String()
+			write("250 OK")
+		case strings.HasPrefix(command, "QUIT"):
+			write("221 bye")
+			return
+		default:
+			write("250 OK")
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+// MemorySender keeps every sent message in memory, meant for tests and local development.
+type MemorySender struct {
+	m        sync.Mutex
+	messages []Message
+}
+
+func (s *MemorySender) Send(_ context.Context, message *Message) error {
+	if err := message.validate(); err != nil {
+		return err
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.messages = append(s.messages, *message)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far.
+func (s *MemorySender) Messages() []Message {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	sender := &SMTPSender{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+	}
+
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return sender
+}
+
+// SMTPSender delivers messages through an SMTP relay, upgrading the connection with STARTTLS when offered.
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := message.build(s.from)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from, message.To, data)
+}
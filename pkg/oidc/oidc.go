@@ -0,0 +1,312 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/raulaguila/go-api/pkg/keyset"
+)
+
+var (
+	ErrDiscovery      = errors.New("oidc discovery failed")
+	ErrExchange       = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken = errors.New("invalid oidc id token")
+)
+
+// keysRefreshInterval limits how often an unknown kid makes the provider keys be fetched again.
+const keysRefreshInterval = time.Minute
+
+// signingMethods are the id token algorithms accepted from providers.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type (
+	Config struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		RedirectURL  string
+		Scopes       []string
+		HTTPClient   *http.Client
+	}
+
+	// Metadata is the part of the provider configuration (OpenID Connect Discovery 1.0) the client uses.
+	Metadata struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	// IDToken holds the verified claims of an id token. Claims keeps all of them, for the profile rules.
+	IDToken struct {
+		Issuer            string
+		Subject           string
+		Email             string
+		EmailVerified     bool
+		Name              string
+		PreferredUsername string
+		Claims            map[string]any
+	}
+
+	// Provider runs the authorization code flow with PKCE against an OpenID Connect issuer. Its configuration
+	// is discovered on first use and its keys are fetched again whenever a token is signed by an unknown one.
+	Provider struct {
+		config Config
+
+		mu            sync.Mutex
+		metadata      *Metadata
+		keys          map[string]crypto.PublicKey
+		keysFetchedAt time.Time
+	}
+)
+
+func NewProvider(config Config) *Provider {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid"}
+	}
+
+	return &Provider{config: config}
+}
+
+// Issuer returns the configured issuer, which the id tokens must carry as iss.
+func (s *Provider) Issuer() string {
+	return s.config.Issuer
+}
+
+// RandomToken returns a random URL safe string, for states, nonces and PKCE verifiers.
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Challenge returns the S256 PKCE challenge of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the provider URL the user is sent to for logging in.
+func (s *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	metadata, err := s.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.config.ClientID},
+		"redirect_uri":          {s.config.RedirectURL},
+		"scope":                 {strings.Join(s.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {Challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the verified id token, which must carry nonce.
+func (s *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*IDToken, error) {
+	metadata, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(s.config.ClientSecret))
+
+	var response struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := s.do(request, &response); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExchange, err)
+	}
+
+	if response.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id token", ErrExchange)
+	}
+
+	return s.Verify(ctx, response.IDToken, nonce)
+}
+
+// Verify checks the id token signature, issuer, audience, expiration and nonce.
+func (s *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return s.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(s.config.Issuer),
+		jwt.WithAudience(s.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	idToken := &IDToken{Claims: claims}
+	idToken.Issuer, _ = claims["iss"].(string)
+	idToken.Subject, _ = claims["sub"].(string)
+	idToken.Email, _ = claims["email"].(string)
+	idToken.Name, _ = claims["name"].(string)
+	idToken.PreferredUsername, _ = claims["preferred_username"].(string)
+
+	// Some providers send email_verified as a string.
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		idToken.EmailVerified = verified
+	case string:
+		idToken.EmailVerified = verified == "true"
+	}
+
+	if idToken.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	return idToken, nil
+}
+
+func (s *Provider) discover(ctx context.Context) (*Metadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.metadata != nil {
+		return s.metadata, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{}
+	if err := s.do(request, metadata); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	if metadata.Issuer != s.config.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscovery, metadata.Issuer, s.config.Issuer)
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata", ErrDiscovery)
+	}
+
+	s.metadata = metadata
+	return metadata, nil
+}
+
+// publicKey returns the provider key with the kid, or its only key when the token has no kid.
+func (s *Provider) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	metadata, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(s.keysFetchedAt) < keysRefreshInterval {
+		return nil, keyset.ErrUnknownKey
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := &keyset.JWKS{}
+	if err := s.do(request, jwks); err != nil {
+		return nil, err
+	}
+
+	s.keys, s.keysFetchedAt = make(map[string]crypto.PublicKey, len(jwks.Keys)), time.Now()
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		if key, err := jwk.PublicKey(); err == nil {
+			s.keys[jwk.Kid] = key
+		}
+	}
+
+	if key, ok := s.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, keyset.ErrUnknownKey
+}
+
+func (s *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *Provider) do(request *http.Request, output any) error {
+	response, err := s.config.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(output)
+}
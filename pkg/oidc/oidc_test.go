@@ -0,0 +1,145 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raulaguila/go-api/pkg/oidc"
+	"github.com/raulaguila/go-api/pkg/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost/auth/oidc/callback"
+
+func newProvider(t *testing.T) (*oidc.Provider, *oidctest.Server) {
+	t.Helper()
+
+	mock, err := oidctest.New("client", "secret")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	mock.Issuer = server.URL
+
+	return oidc.NewProvider(oidc.Config{
+		Issuer:       server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email"},
+	}), mock
+}
+
+// authorize follows the authorization URL and returns the code and state sent back to the redirect URL.
+func authorize(t *testing.T, authURL string) (string, string) {
+	t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	response, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	provider, mock := newProvider(t)
+	mock.Claims = map[string]any{"email": "user@example.com", "email_verified": true, "name": "Mock User", "groups": []string{"admins"}}
+
+	verifier, err := oidc.RandomToken()
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, oidc.Challenge(verifier), parsed.Query().Get("code_challenge"))
+	assert.Equal(t, "openid email", parsed.Query().Get("scope"))
+
+	code, state := authorize(t, authURL)
+	assert.Equal(t, "state", state)
+
+	idToken, err := provider.Exchange(context.Background(), code, verifier, "nonce")
+	require.NoError(t, err)
+	assert.Equal(t, mock.Issuer, idToken.Issuer)
+	assert.Equal(t, "mock-user", idToken.Subject)
+	assert.Equal(t, "user@example.com", idToken.Email)
+	assert.True(t, idToken.EmailVerified)
+	assert.Equal(t, "Mock User", idToken.Name)
+
+	profile, ok := oidc.MatchProfile(idToken.Claims, []oidc.Rule{{Claim: "groups", Value: "admins", Profile: "ADMIN"}})
+	assert.True(t, ok)
+	assert.Equal(t, "ADMIN", profile)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange, "codes are single use")
+}
+
+func TestExchangeRejects(t *testing.T) {
+	provider, _ := newProvider(t)
+	verifier, err := oidc.RandomToken()
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", verifier)
+	require.NoError(t, err)
+	code, _ := authorize(t, authURL)
+
+	_, err = provider.Exchange(context.Background(), code, "wrong-verifier", "nonce")
+	assert.ErrorIs(t, err, oidc.ErrExchange)
+
+	code, _ = authorize(t, authURL)
+	_, err = provider.Exchange(context.Background(), code, verifier, "other-nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestVerify(t *testing.T) {
+	provider, mock := newProvider(t)
+
+	idToken, err := mock.IDToken("subject", "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = provider.Verify(context.Background(), idToken, "nonce")
+	require.NoError(t, err)
+
+	expired, err := mock.IDToken("subject", "nonce", -time.Minute)
+	require.NoError(t, err)
+	_, err = provider.Verify(context.Background(), expired, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+
+	other, err := oidctest.New("client", "secret")
+	require.NoError(t, err)
+	other.Issuer = mock.Issuer
+	forged, err := other.IDToken("subject", "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = provider.Verify(context.Background(), forged, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken, "signed by an unknown key")
+
+	mock.ClientID = "another-client"
+	foreign, err := mock.IDToken("subject", "nonce", time.Minute)
+	require.NoError(t, err)
+	_, err = provider.Verify(context.Background(), foreign, "nonce")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken, "issued for another client")
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	mock, err := oidctest.New("client", "secret")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	mock.Issuer = "https://another.example.com"
+
+	provider := oidc.NewProvider(oidc.Config{Issuer: server.URL, ClientID: "client"})
+	_, err = provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
@@ -0,0 +1,193 @@
+// Package oidctest is a minimal OpenID Connect provider for tests and local development. Its authorization
+// endpoint logs in right away, without asking for credentials, as the configured subject.
+package oidctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/raulaguila/go-api/pkg/keyset"
+	"github.com/raulaguila/go-api/pkg/oidc"
+)
+
+type (
+	// Server is an http.Handler serving the provider under Issuer, which must be set to the URL it is reached at.
+	Server struct {
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+
+		// Subject and Claims are put in the id tokens. A login_hint on the authorization request replaces Subject.
+		Subject string
+		Claims  map[string]any
+
+		keys  *keyset.KeySet
+		mux   *http.ServeMux
+		mu    sync.Mutex
+		codes map[string]*authorization
+	}
+
+	authorization struct {
+		redirectURI string
+		challenge   string
+		nonce       string
+		subject     string
+	}
+)
+
+func New(clientID, clientSecret string) (*Server, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyset.NewKey(privateKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := keyset.New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Subject:      "mock-user",
+		Claims:       map[string]any{},
+		keys:         keys,
+		mux:          http.NewServeMux(),
+		codes:        map[string]*authorization{},
+	}
+
+	s.mux.HandleFunc("GET /.well-known/openid-configuration", s.discovery)
+	s.mux.HandleFunc("GET /authorize", s.authorize)
+	s.mux.HandleFunc("POST /token", s.token)
+	s.mux.HandleFunc("GET /jwks", s.jwks)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// IDToken signs an id token for the subject, as the token endpoint does.
+func (s *Server) IDToken(subject, nonce string, expire time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	maps.Copy(claims, s.Claims)
+	maps.Copy(claims, jwt.MapClaims{
+		"iss":   s.Issuer,
+		"aud":   s.ClientID,
+		"sub":   subject,
+		"nonce": nonce,
+		"iat":   now.Unix(),
+		"exp":   now.Add(expire).Unix(),
+	})
+
+	return s.keys.Sign(claims)
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.Metadata{
+		Issuer:                s.Issuer,
+		AuthorizationEndpoint: s.Issuer + "/authorize",
+		TokenEndpoint:         s.Issuer + "/token",
+		JWKSURI:               s.Issuer + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.keys.JWKS())
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" || query.Get("response_type") != "code" || query.Get("client_id") != s.ClientID ||
+		query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code, err := oidc.RandomToken()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	subject := query.Get("login_hint")
+	if subject == "" {
+		subject = s.Subject
+	}
+
+	s.mu.Lock()
+	s.codes[code] = &authorization{
+		redirectURI: query.Get("redirect_uri"),
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		subject:     subject,
+	}
+	s.mu.Unlock()
+
+	callback := redirectURI.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostFormValue("client_id"), r.PostFormValue("client_secret")
+	}
+
+	if clientID != s.ClientID || clientSecret != s.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	auth, ok := s.codes[r.PostFormValue("code")]
+	delete(s.codes, r.PostFormValue("code"))
+	s.mu.Unlock()
+
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != auth.redirectURI ||
+		oidc.Challenge(r.PostFormValue("code_verifier")) != auth.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := s.IDToken(auth.subject, auth.nonce, 5*time.Minute)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": idToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,85 @@
+package oidc
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidRule = errors.New("invalid profile rule")
+
+// Rule maps users whose id token Claim holds Value to the local profile named Profile. Claim may be a
+// dotted path into nested claims, like "realm_access.roles", and match a string or an element of a list.
+type Rule struct {
+	Claim   string
+	Value   string
+	Profile string
+}
+
+// ParseRules parses comma separated "claim=value:profile" rules.
+func ParseRules(rules string) ([]Rule, error) {
+	parsed := make([]Rule, 0)
+	for _, item := range strings.Split(rules, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		claim, rest, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, ErrInvalidRule
+		}
+
+		value, profile, ok := strings.Cut(rest, ":")
+		if !ok || claim == "" || value == "" || profile == "" {
+			return nil, ErrInvalidRule
+		}
+
+		parsed = append(parsed, Rule{Claim: claim, Value: value, Profile: profile})
+	}
+
+	return parsed, nil
+}
+
+// MatchProfile returns the profile of the first rule matching the claims.
+func MatchProfile(claims map[string]any, rules []Rule) (string, bool) {
+	for _, rule := range rules {
+		if matches(lookup(claims, rule.Claim), rule.Value) {
+			return rule.Profile, true
+		}
+	}
+
+	return "", false
+}
+
+func lookup(claims map[string]any, path string) any {
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	return value
+}
+
+func matches(claim any, value string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == value
+	case []any:
+		for _, item := range c {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	case []string:
+		for _, s := range c {
+			if s == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
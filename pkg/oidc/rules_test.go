@@ -0,0 +1,56 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(" groups=admins:ADMIN , realm_access.roles=dev:DEV,")
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Claim: "groups", Value: "admins", Profile: "ADMIN"},
+		{Claim: "realm_access.roles", Value: "dev", Profile: "DEV"},
+	}, rules)
+
+	rules, err = ParseRules("")
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	for _, invalid := range []string{"groups", "groups=admins", "=admins:ADMIN", "groups=:ADMIN", "groups=admins:"} {
+		_, err := ParseRules(invalid)
+		assert.ErrorIs(t, err, ErrInvalidRule, invalid)
+	}
+}
+
+func TestMatchProfile(t *testing.T) {
+	rules := []Rule{
+		{Claim: "groups", Value: "admins", Profile: "ADMIN"},
+		{Claim: "realm_access.roles", Value: "dev", Profile: "DEV"},
+		{Claim: "department", Value: "sales", Profile: "SALES"},
+	}
+
+	tests := []struct {
+		name    string
+		claims  map[string]any
+		profile string
+		ok      bool
+	}{
+		{"list claim", map[string]any{"groups": []any{"users", "admins"}}, "ADMIN", true},
+		{"nested claim", map[string]any{"realm_access": map[string]any{"roles": []any{"dev"}}}, "DEV", true},
+		{"string claim", map[string]any{"department": "sales"}, "SALES", true},
+		{"first rule wins", map[string]any{"groups": []any{"admins"}, "department": "sales"}, "ADMIN", true},
+		{"no match", map[string]any{"groups": []any{"users"}, "department": "support"}, "", false},
+		{"not an object", map[string]any{"realm_access": "dev"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, ok := MatchProfile(tt.claims, rules)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.profile, profile)
+		})
+	}
+}
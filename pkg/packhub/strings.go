@@ -6,3 +6,14 @@ func Capitalize(s string) string {
 	res := strings.TrimSpace(s)
 	return strings.ToUpper(res[:1]) + strings.ToLower(res[1:])
 }
+
+// Truncate cuts s to at most length runes, never splitting one.
+func Truncate(s string, length int) string {
+	for i := range s {
+		if length == 0 {
+			return s[:i]
+		}
+		length--
+	}
+	return s
+}
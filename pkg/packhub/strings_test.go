@@ -1,6 +1,7 @@
 package packhub
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,3 +31,55 @@ func TestCapitalize(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Input  string
+		Length int
+		Output string
+	}{
+		{
+			Name:   "Short",
+			Input:  "Mozilla/5.0",
+			Length: 255,
+			Output: "Mozilla/5.0",
+		},
+		{
+			Name:   "Exact",
+			Input:  "Mozilla",
+			Length: 7,
+			Output: "Mozilla",
+		},
+		{
+			Name:   "Long",
+			Input:  strings.Repeat("a", 300),
+			Length: 255,
+			Output: strings.Repeat("a", 255),
+		},
+		{
+			Name:   "Multibyte",
+			Input:  strings.Repeat("ç", 150),
+			Length: 255,
+			Output: strings.Repeat("ç", 150),
+		},
+		{
+			Name:   "Multibyte long",
+			Input:  "São Paulo",
+			Length: 2,
+			Output: "Sã",
+		},
+		{
+			Name:   "Empty",
+			Input:  "",
+			Length: 0,
+			Output: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			assert.Equal(t, tt.Output, Truncate(tt.Input, tt.Length))
+		})
+	}
+}
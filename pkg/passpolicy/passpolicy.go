@@ -0,0 +1,91 @@
+package passpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrTooShort      = errors.New("password too short")
+	ErrMissingUpper  = errors.New("password missing upper case letter")
+	ErrMissingLower  = errors.New("password missing lower case letter")
+	ErrMissingDigit  = errors.New("password missing digit")
+	ErrMissingSymbol = errors.New("password missing symbol")
+	ErrCommon        = errors.New("password too common")
+	ErrPersonalData  = errors.New("password equal to personal data")
+)
+
+//go:embed denylist.txt
+var denylistFile string
+
+// denylist holds the lower case common passwords from denylist.txt.
+var denylist = func() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(denylistFile))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			passwords[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return passwords
+}()
+
+// Policy describes the rules a new password must follow. History is the number of previous passwords
+// that can't be reused; checking it is up to the caller, as only it knows the stored hashes.
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	History       int
+}
+
+// Validate returns the first rule the password breaks, or nil. personal holds values the password
+// can't be equal to, regardless of case, such as the username and the email.
+func (p *Policy) Validate(password string, personal ...string) error {
+	if utf8.RuneCountInString(password) < max(p.MinLength, 1) {
+		return ErrTooShort
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return ErrMissingUpper
+	case p.RequireLower && !lower:
+		return ErrMissingLower
+	case p.RequireDigit && !digit:
+		return ErrMissingDigit
+	case p.RequireSymbol && !symbol:
+		return ErrMissingSymbol
+	}
+
+	if _, ok := denylist[strings.ToLower(password)]; ok {
+		return ErrCommon
+	}
+
+	for _, value := range personal {
+		if value != "" && strings.EqualFold(password, value) {
+			return ErrPersonalData
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package passpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	strict := &Policy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		password string
+		personal []string
+		expected error
+	}{
+		{"valid", strict, "Sup3r-Secret", nil, nil},
+		{"empty", &Policy{}, "", nil, ErrTooShort},
+		{"too short", strict, "Ab1-", nil, ErrTooShort},
+		{"counts runes", &Policy{MinLength: 4}, "ção!", nil, nil},
+		{"missing upper", strict, "sup3r-secret", nil, ErrMissingUpper},
+		{"missing lower", strict, "SUP3R-SECRET", nil, ErrMissingLower},
+		{"missing digit", strict, "Super-Secret", nil, ErrMissingDigit},
+		{"missing symbol", strict, "Sup3rSecret", nil, ErrMissingSymbol},
+		{"common", &Policy{MinLength: 8}, "Password123", nil, ErrCommon},
+		{"equal to username", &Policy{MinLength: 8}, "John.Cena", []string{"john.cena", "john.cena@email.com"}, ErrPersonalData},
+		{"equal to email", &Policy{MinLength: 8}, "john.cena@email.com", []string{"john.cena", "john.cena@email.com"}, ErrPersonalData},
+		{"ignores empty personal data", &Policy{MinLength: 8}, "unrelated words", []string{""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.policy.Validate(tt.password, tt.personal...), tt.expected)
+		})
+	}
+}
+
+func TestDenylist(t *testing.T) {
+	assert.NotEmpty(t, denylist)
+	assert.Contains(t, denylist, "123456")
+	assert.NotContains(t, denylist, "")
+	for password := range denylist {
+		assert.NotContains(t, password, "#")
+	}
+}
@@ -0,0 +1,225 @@
+package pgfilter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidFilter is returned when a filter names an unknown field or operator, or has an invalid value.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Operators accepted in filter[<field>][<operator>]=<value>. Values of in and between are comma separated,
+// is_null takes true or false, and like and ilike match values containing the text.
+const (
+	OperatorEqual          string = "eq"
+	OperatorNotEqual       string = "ne"
+	OperatorGreater        string = "gt"
+	OperatorGreaterOrEqual string = "gte"
+	OperatorLess           string = "lt"
+	OperatorLessOrEqual    string = "lte"
+	OperatorLike           string = "like"
+	OperatorILike          string = "ilike"
+	OperatorIn             string = "in"
+	OperatorBetween        string = "between"
+	OperatorIsNull         string = "is_null"
+)
+
+const (
+	maxConditions = 20
+	maxInValues   = 100
+)
+
+// FieldType tells how filter values are parsed and which operators apply to a field.
+type FieldType uint8
+
+const (
+	FieldString FieldType = iota
+	FieldInteger
+	FieldBool
+	FieldTime
+)
+
+type (
+	// Condition is one filter[<field>][<operator>]=<value> query parameter. A missing operator means eq.
+	Condition struct {
+		Field    string
+		Operator string
+		Value    string
+	}
+
+	// FilterField is the column a filterable field stands for.
+	FilterField struct {
+		Column string
+		Type   FieldType
+	}
+
+	// FilterFields maps the names clients may filter by to their columns.
+	FilterFields map[string]FilterField
+)
+
+var comparisons = map[string]string{
+	OperatorEqual:          "=",
+	OperatorNotEqual:       "<>",
+	OperatorGreater:        ">",
+	OperatorGreaterOrEqual: ">=",
+	OperatorLess:           "<",
+	OperatorLessOrEqual:    "<=",
+}
+
+// BindQuery collects the filter[<field>][<operator>] parameters into Conditions, sorted so equal queries
+// build equal SQL. Malformed keys are kept with no field, to be refused by ApplyConditions.
+func (s *Filter) BindQuery(query url.Values) error {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	s.Conditions = nil
+	for _, key := range keys {
+		condition := Condition{Operator: OperatorEqual}
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+		switch {
+		case !strings.HasSuffix(key, "]") || len(parts) > 2:
+		case len(parts) == 2:
+			condition.Field, condition.Operator = parts[0], parts[1]
+		default:
+			condition.Field = parts[0]
+		}
+
+		for _, value := range query[key] {
+			condition.Value = value
+			s.Conditions = append(s.Conditions, condition)
+		}
+	}
+
+	return nil
+}
+
+// ApplyConditions returns the conditions joined by AND, or nil without conditions. Fields are looked up
+// in fields and values bound as parameters; anything not fitting them returns ErrInvalidFilter.
+func (s *Filter) ApplyConditions(fields FilterFields) (clause.Expression, error) {
+	if len(s.Conditions) == 0 {
+		return nil, nil
+	}
+
+	if len(s.Conditions) > maxConditions {
+		return nil, ErrInvalidFilter
+	}
+
+	conditions := make([]string, len(s.Conditions))
+	vars := make([]any, 0, len(s.Conditions))
+	for i, condition := range s.Conditions {
+		field, ok := fields[condition.Field]
+		if !ok {
+			return nil, ErrInvalidFilter
+		}
+
+		sql, values, err := field.build(condition.Operator, condition.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions[i] = sql
+		vars = append(vars, values...)
+	}
+
+	return clause.Expr{SQL: strings.Join(conditions, " AND "), Vars: vars}, nil
+}
+
+// build returns the SQL of the operator applied to the field, with its parsed values.
+func (s FilterField) build(operator, value string) (string, []any, error) {
+	if sign, ok := comparisons[operator]; ok {
+		if s.Type == FieldBool && operator != OperatorEqual && operator != OperatorNotEqual {
+			return "", nil, ErrInvalidFilter
+		}
+
+		parsed, err := s.parse(value)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s ?", s.Column, sign), []any{parsed}, nil
+	}
+
+	switch operator {
+	case OperatorLike, OperatorILike:
+		if s.Type != FieldString || value == "" {
+			return "", nil, ErrInvalidFilter
+		}
+		return fmt.Sprintf(`%s %s ? ESCAPE '\'`, s.Column, strings.ToUpper(operator)), []any{"%" + likeEscaper.Replace(value) + "%"}, nil
+	case OperatorIn:
+		items := strings.Split(value, ",")
+		if len(items) > maxInValues {
+			return "", nil, ErrInvalidFilter
+		}
+
+		parsed := make([]any, len(items))
+		for i, item := range items {
+			var err error
+			if parsed[i], err = s.parse(item); err != nil {
+				return "", nil, err
+			}
+		}
+		return s.Column + " IN ?", []any{parsed}, nil
+	case OperatorBetween:
+		low, high, ok := strings.Cut(value, ",")
+		if !ok || s.Type == FieldBool {
+			return "", nil, ErrInvalidFilter
+		}
+
+		parsedLow, err := s.parse(low)
+		if err != nil {
+			return "", nil, err
+		}
+		parsedHigh, err := s.parse(high)
+		if err != nil {
+			return "", nil, err
+		}
+		return s.Column + " BETWEEN ? AND ?", []any{parsedLow, parsedHigh}, nil
+	case OperatorIsNull:
+		isNull, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", nil, ErrInvalidFilter
+		}
+
+		if isNull {
+			return s.Column + " IS NULL", nil, nil
+		}
+		return s.Column + " IS NOT NULL", nil, nil
+	}
+
+	return "", nil, ErrInvalidFilter
+}
+
+// parse converts the value to the field's type. Times are RFC 3339 timestamps or dates.
+func (s FilterField) parse(value string) (any, error) {
+	switch s.Type {
+	case FieldInteger:
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed, nil
+		}
+	case FieldBool:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed, nil
+		}
+	case FieldTime:
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				return parsed, nil
+			}
+		}
+	default:
+		return value, nil
+	}
+
+	return nil, ErrInvalidFilter
+}
@@ -0,0 +1,111 @@
+package pgfilter
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestBindQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []Condition
+	}{
+		{"no_filters", "search=john&page=1", nil},
+		{"operator", "filter[name][ilike]=jo", []Condition{{"name", "ilike", "jo"}}},
+		{"default_operator", "filter[status]=true", []Condition{{"status", "eq", "true"}}},
+		{"sorted", "filter[name][eq]=a&filter[created_at][gte]=2026-01-01", []Condition{{"created_at", "gte", "2026-01-01"}, {"name", "eq", "a"}}},
+		{"repeated", "filter[id][ne]=1&filter[id][ne]=2", []Condition{{"id", "ne", "1"}, {"id", "ne", "2"}}},
+		{"too_deep", "filter[name][eq][x]=a", []Condition{{"", "eq", "a"}}},
+		{"unclosed", "filter[name=a", []Condition{{"", "eq", "a"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filter := Filter{}
+			if err := filter.BindQuery(query); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(filter.Conditions, tt.expected) {
+				t.Errorf("expected: %v, got: %v", tt.expected, filter.Conditions)
+			}
+		})
+	}
+}
+
+func TestApplyConditions(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := FilterFields{
+		"id":         {Column: "users.id", Type: FieldInteger},
+		"name":       {Column: "users.name", Type: FieldString},
+		"status":     {Column: "users.status", Type: FieldBool},
+		"created_at": {Column: "users.created_at", Type: FieldTime},
+	}
+
+	tests := []struct {
+		name       string
+		conditions []Condition
+		sql        string
+		vars       []any
+		err        error
+	}{
+		{"no_conditions", nil, `SELECT * FROM "users"`, nil, nil},
+		{"equal", []Condition{{"id", "eq", "7"}}, `SELECT * FROM "users" WHERE users.id = $1`, []any{int64(7)}, nil},
+		{"ilike", []Condition{{"name", "ilike", "j%o"}}, `SELECT * FROM "users" WHERE users.name ILIKE $1 ESCAPE '\'`, []any{`%j\%o%`}, nil},
+		{"in", []Condition{{"id", "in", "1,2,3"}}, `SELECT * FROM "users" WHERE users.id IN ($1,$2,$3)`, []any{int64(1), int64(2), int64(3)}, nil},
+		{"between", []Condition{{"created_at", "between", "2026-01-01,2026-02-01T12:00:00Z"}}, `SELECT * FROM "users" WHERE users.created_at BETWEEN $1 AND $2`,
+			[]any{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)}, nil},
+		{"is_null", []Condition{{"name", "is_null", "false"}}, `SELECT * FROM "users" WHERE users.name IS NOT NULL`, nil, nil},
+		{"combined", []Condition{{"status", "eq", "true"}, {"created_at", "gte", "2026-01-01"}}, `SELECT * FROM "users" WHERE users.status = $1 AND users.created_at >= $2`,
+			[]any{true, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}, nil},
+		{"injected_value", []Condition{{"name", "eq", "' OR 1=1 --"}}, `SELECT * FROM "users" WHERE users.name = $1`, []any{"' OR 1=1 --"}, nil},
+		{"unknown_field", []Condition{{"password", "eq", "x"}}, "", nil, ErrInvalidFilter},
+		{"unknown_operator", []Condition{{"name", "regex", "x"}}, "", nil, ErrInvalidFilter},
+		{"invalid_integer", []Condition{{"id", "eq", "1 OR 1=1"}}, "", nil, ErrInvalidFilter},
+		{"invalid_time", []Condition{{"created_at", "lt", "yesterday"}}, "", nil, ErrInvalidFilter},
+		{"like_on_integer", []Condition{{"id", "like", "1"}}, "", nil, ErrInvalidFilter},
+		{"ordering_on_bool", []Condition{{"status", "gt", "true"}}, "", nil, ErrInvalidFilter},
+		{"between_one_value", []Condition{{"id", "between", "1"}}, "", nil, ErrInvalidFilter},
+		{"invalid_is_null", []Condition{{"name", "is_null", "maybe"}}, "", nil, ErrInvalidFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{Conditions: tt.conditions}
+			where, err := filter.ApplyConditions(fields)
+			if err != tt.err {
+				t.Fatalf("expected error: %v, got: %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			tx := db.Table("users")
+			if where != nil {
+				tx = tx.Where(where)
+			}
+			statement := tx.Find(&[]map[string]any{}).Statement
+
+			if got := statement.SQL.String(); got != tt.sql {
+				t.Errorf("expected: %v, got: %v", tt.sql, got)
+			}
+			if (len(statement.Vars) > 0 || len(tt.vars) > 0) && !reflect.DeepEqual(statement.Vars, tt.vars) {
+				t.Errorf("expected: %v, got: %v", tt.vars, statement.Vars)
+			}
+		})
+	}
+}
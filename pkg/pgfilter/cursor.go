@@ -0,0 +1,294 @@
+package pgfilter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed, not signed by the API or made for another
+// sort, and for cursors given without a limit or both ways at once.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursors point to the pages around the one read, empty when there are no rows that way.
+type Cursors struct {
+	Next string
+	Prev string
+}
+
+type (
+	// cursor is the signed content of a cursor: the sort it was made for and the key of its row.
+	cursor struct {
+		Sort string        `json:"s"`
+		Key  []cursorValue `json:"k"`
+	}
+
+	// cursorValue keeps the type of a key value, so it is bound as it was read.
+	cursorValue struct {
+		Type  string `json:"t"`
+		Value string `json:"v"`
+	}
+)
+
+// cursorKey returns the key cursors are signed with. Without one, cursor pagination is disabled.
+func cursorKey() []byte {
+	return []byte(os.Getenv("API_CURSOR_SECRET"))
+}
+
+// Counted reports whether the total of rows should be counted: always when reading by page, whose totals
+// clients rely on, and by cursor only when asked by WithCount, as counting is what cursors spare on large tables.
+func (s *Filter) Counted() bool {
+	if s.After == "" && s.Before == "" {
+		return true
+	}
+
+	return s.WithCount != nil && *s.WithCount
+}
+
+// ReadPage reads the page of rows selected by db, which must hold the resource's model, joins and conditions,
+// returning the values of tieBreaker, the primary key, in order. Pages start after or before the cursor
+// when one is given, keyed on the sort columns and the tie breaker, or at Page otherwise.
+func (s *Filter) ReadPage(db *gorm.DB, columns SortColumns, tieBreaker string) ([]uint, *Cursors, error) {
+	return s.readPage(db, nil, columns, tieBreaker)
+}
+
+// ReadRankedPage reads the page like ReadPage, but ordered by rank, descending, before the sort columns.
+// The rank depends on the search, not on the row alone, so ranked pages are read at Page only: cursors
+// are neither returned nor accepted. A nil rank reads the page like ReadPage.
+func (s *Filter) ReadRankedPage(db *gorm.DB, rank clause.Expression, columns SortColumns, tieBreaker string) ([]uint, *Cursors, error) {
+	return s.readPage(db, rank, columns, tieBreaker)
+}
+
+func (s *Filter) readPage(db *gorm.DB, rank clause.Expression, columns SortColumns, tieBreaker string) ([]uint, *Cursors, error) {
+	orderBy, err := s.ApplyOrder(columns, tieBreaker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backward := s.Before != ""
+	token := s.After
+	if backward {
+		token = s.Before
+	}
+
+	if token != "" && (rank != nil || s.Limit <= 0 || (s.After != "" && s.Before != "")) {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	sort := sortSpec(orderBy)
+	if backward {
+		for i := range orderBy.Columns {
+			orderBy.Columns[i].Desc = !orderBy.Columns[i].Desc
+		}
+	}
+
+	tieIndex := 0
+	selects := make([]string, len(orderBy.Columns))
+	for i, column := range orderBy.Columns {
+		selects[i] = fmt.Sprintf("%s AS cursor_%d", column.Column.Name, i)
+		if column.Column.Name == tieBreaker {
+			tieIndex = i
+		}
+	}
+
+	offset := 0
+	if token != "" {
+		key, err := decodeCursor(token, sort)
+		if err != nil || len(key) != len(orderBy.Columns) {
+			return nil, nil, ErrInvalidCursor
+		}
+		db = db.Where(keysetCondition(orderBy, key))
+		db = db.Limit(s.Limit + 1)
+	} else if ok, pageOffset, limit := s.ApplyPagination(); ok {
+		offset = pageOffset
+		db = db.Offset(offset).Limit(limit + 1)
+	}
+
+	if rank != nil {
+		db = db.Order(rankedOrder(rank, orderBy))
+	} else {
+		db = db.Order(orderBy)
+	}
+
+	rows := make([]map[string]any, 0)
+	if err := db.Select(selects).Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+
+	more := s.Limit > 0 && len(rows) > s.Limit
+	if more {
+		rows = rows[:s.Limit]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		id, ok := row[fmt.Sprintf("cursor_%d", tieIndex)].(int64)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected %T primary key", row[fmt.Sprintf("cursor_%d", tieIndex)])
+		}
+		ids[i] = uint(id)
+	}
+
+	cursors := &Cursors{}
+	if len(rows) == 0 || rank != nil || len(cursorKey()) == 0 {
+		return ids, cursors, nil
+	}
+
+	if (backward && more) || (!backward && (s.After != "" || offset > 0)) {
+		if cursors.Prev, err = encodeCursor(sort, rows[0]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if backward || more {
+		if cursors.Next, err = encodeCursor(sort, rows[len(rows)-1]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ids, cursors, nil
+}
+
+// rankedOrder orders by the rank, descending, then by the columns of orderBy.
+func rankedOrder(rank clause.Expression, orderBy clause.OrderBy) clause.OrderBy {
+	sql := "? DESC"
+	for _, column := range orderBy.Columns {
+		sql += ", " + column.Column.Name
+		if column.Desc {
+			sql += " DESC"
+		}
+	}
+
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: []any{rank}}}
+}
+
+// sortSpec describes the ordering, so cursors made for another sort are refused.
+func sortSpec(orderBy clause.OrderBy) string {
+	spec := make([]string, len(orderBy.Columns))
+	for i, column := range orderBy.Columns {
+		spec[i] = column.Column.Name
+		if column.Desc {
+			spec[i] = "-" + spec[i]
+		}
+	}
+
+	return strings.Join(spec, ",")
+}
+
+// keysetCondition selects the rows coming after the key in the ordering: those greater, or less when
+// descending, in the first column, or equal in it and after the key in the next ones.
+func keysetCondition(orderBy clause.OrderBy, key []any) clause.Expression {
+	conditions := make([]string, len(orderBy.Columns))
+	vars := make([]any, 0)
+	for i, column := range orderBy.Columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, orderBy.Columns[j].Column.Name+" = ?")
+			vars = append(vars, key[j])
+		}
+
+		operator := ">"
+		if column.Desc {
+			operator = "<"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s ?", column.Column.Name, operator))
+		vars = append(vars, key[i])
+
+		conditions[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars}
+}
+
+// encodeCursor signs the key of the row, read from its cursor_<n> columns.
+func encodeCursor(sort string, row map[string]any) (string, error) {
+	content := cursor{Sort: sort, Key: make([]cursorValue, len(row))}
+	for i := range content.Key {
+		switch value := row[fmt.Sprintf("cursor_%d", i)].(type) {
+		case string:
+			content.Key[i] = cursorValue{Type: "s", Value: value}
+		case int64:
+			content.Key[i] = cursorValue{Type: "i", Value: strconv.FormatInt(value, 10)}
+		case int32:
+			content.Key[i] = cursorValue{Type: "i", Value: strconv.FormatInt(int64(value), 10)}
+		case bool:
+			content.Key[i] = cursorValue{Type: "b", Value: strconv.FormatBool(value)}
+		case time.Time:
+			content.Key[i] = cursorValue{Type: "t", Value: value.Format(time.RFC3339Nano)}
+		default:
+			return "", fmt.Errorf("unsupported %T cursor value", value)
+		}
+	}
+
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign(payload)), nil
+}
+
+// decodeCursor verifies the cursor was signed for the sort and returns its key.
+func decodeCursor(token, sort string) ([]any, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok || len(cursorKey()) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	content := cursor{}
+	if err := json.Unmarshal(payload, &content); err != nil || content.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	key := make([]any, len(content.Key))
+	for i, value := range content.Key {
+		switch value.Type {
+		case "s":
+			key[i] = value.Value
+		case "i":
+			key[i], err = strconv.ParseInt(value.Value, 10, 64)
+		case "b":
+			key[i], err = strconv.ParseBool(value.Value)
+		case "t":
+			key[i], err = time.Parse(time.RFC3339Nano, value.Value)
+		default:
+			err = ErrInvalidCursor
+		}
+
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return key, nil
+}
+
+func sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, cursorKey())
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
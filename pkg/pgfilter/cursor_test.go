@@ -0,0 +1,186 @@
+package pgfilter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Setenv("API_CURSOR_SECRET", "secret")
+
+	updatedAt := time.Date(2026, 3, 4, 5, 6, 7, 891011000, time.FixedZone("AMT", -4*60*60))
+	row := map[string]any{"cursor_0": "John", "cursor_1": updatedAt, "cursor_2": true, "cursor_3": int64(42)}
+
+	token, err := encodeCursor("users.name,-users.updated_at,users.status,users.id", row)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := decodeCursor(token, "users.name,-users.updated_at,users.status,users.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key) != 4 || key[0] != "John" || !key[1].(time.Time).Equal(updatedAt) || key[2] != true || key[3] != int64(42) {
+		t.Errorf("expected: %v, got: %v", row, key)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	t.Setenv("API_CURSOR_SECRET", "secret")
+
+	token, err := encodeCursor("users.name,users.id", map[string]any{"cursor_0": "John", "cursor_1": int64(42)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name   string
+		token  string
+		sort   string
+		secret string
+	}{
+		{"other_sort", token, "-users.name,users.id", "secret"},
+		{"other_secret", token, "users.name,users.id", "other"},
+		{"no_secret", token, "users.name,users.id", ""},
+		{"tampered_payload", payload[:len(payload)-2] + "xx." + signature, "users.name,users.id", "secret"},
+		{"tampered_signature", payload + "." + signature[:len(signature)-2] + "xx", "users.name,users.id", "secret"},
+		{"unsigned", payload, "users.name,users.id", "secret"},
+		{"garbage", "not a cursor", "users.name,users.id", "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("API_CURSOR_SECRET", tt.secret)
+			if _, err := decodeCursor(tt.token, tt.sort); err != ErrInvalidCursor {
+				t.Errorf("expected: %v, got: %v", ErrInvalidCursor, err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderBy := clause.OrderBy{Columns: []clause.OrderByColumn{
+		{Column: clause.Column{Name: "users.name", Raw: true}},
+		{Column: clause.Column{Name: "users.updated_at", Raw: true}, Desc: true},
+		{Column: clause.Column{Name: "users.id", Raw: true}},
+	}}
+	key := []any{"John", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), int64(42)}
+
+	statement := db.Table("users").Where(keysetCondition(orderBy, key)).Find(&[]map[string]any{}).Statement
+	expected := `SELECT * FROM "users" WHERE ((users.name > $1) OR (users.name = $2 AND users.updated_at < $3) OR (users.name = $4 AND users.updated_at = $5 AND users.id > $6))`
+	if got := statement.SQL.String(); got != expected {
+		t.Errorf("expected: %v, got: %v", expected, got)
+	}
+
+	vars := []any{key[0], key[0], key[1], key[0], key[1], key[2]}
+	if !reflect.DeepEqual(statement.Vars, vars) {
+		t.Errorf("expected: %v, got: %v", vars, statement.Vars)
+	}
+}
+
+func TestReadPageInvalidCursor(t *testing.T) {
+	t.Setenv("API_CURSOR_SECRET", "secret")
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := SortColumns{"name": "users.name"}
+	token, err := encodeCursor("users.name,users.id", map[string]any{"cursor_0": "John", "cursor_1": int64(42)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+	}{
+		{"without_limit", Filter{Sort: "name", Order: "asc", After: token}},
+		{"both_ways", Filter{Sort: "name", Order: "asc", Limit: 10, After: token, Before: token}},
+		{"other_sort", Filter{Sort: "name", Order: "desc", Limit: 10, After: token}},
+		{"forged", Filter{Sort: "name", Order: "asc", Limit: 10, Before: "e30.AAAA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := tt.filter.ReadPage(db.Table("users"), columns, "users.id"); err != ErrInvalidCursor {
+				t.Errorf("expected: %v, got: %v", ErrInvalidCursor, err)
+			}
+		})
+	}
+
+	filter := Filter{Sort: "name", Order: "asc", Limit: 10, After: token}
+	if ids, cursors, err := filter.ReadPage(db.Table("users"), columns, "users.id"); err != nil || len(ids) != 0 || *cursors != (Cursors{}) {
+		t.Errorf("expected an empty page, got: %v, %v, %v", ids, cursors, err)
+	}
+}
+
+func TestCounted(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{"pages", Filter{}, true},
+		{"cursor", Filter{After: "cursor"}, false},
+		{"cursor_with_count", Filter{Before: "cursor", WithCount: &yes}, true},
+		{"cursor_without_count", Filter{After: "cursor", WithCount: &no}, false},
+		{"pages_without_count", Filter{WithCount: &no}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Counted(); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadRankedPage(t *testing.T) {
+	t.Setenv("API_CURSOR_SECRET", "secret")
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := SortColumns{"name": "users.name"}
+	rank := clause.Expr{SQL: "ts_rank(users.search_vector, to_tsquery('simple', ?))", Vars: []any{"jo:*"}}
+
+	token, err := encodeCursor("users.name,users.id", map[string]any{"cursor_0": "John", "cursor_1": int64(42)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := Filter{Sort: "name", Order: "asc", Limit: 10, After: token}
+	if _, _, err := filter.ReadRankedPage(db.Table("users"), rank, columns, "users.id"); err != ErrInvalidCursor {
+		t.Errorf("expected: %v, got: %v", ErrInvalidCursor, err)
+	}
+
+	tx := db.Table("users")
+	filter = Filter{Sort: "-name", Order: "asc", Page: 2, Limit: 10}
+	if ids, cursors, err := filter.ReadRankedPage(tx, rank, columns, "users.id"); err != nil || len(ids) != 0 || *cursors != (Cursors{}) {
+		t.Fatalf("expected an empty page, got: %v, %v, %v", ids, cursors, err)
+	}
+
+	expected := `SELECT users.name AS cursor_0,users.id AS cursor_1 FROM "users" ORDER BY ts_rank(users.search_vector, to_tsquery('simple', $1)) DESC, users.name DESC, users.id LIMIT $2 OFFSET $3`
+	if got := tx.Statement.SQL.String(); got != expected {
+		t.Errorf("expected: %v, got: %v", expected, got)
+	}
+}
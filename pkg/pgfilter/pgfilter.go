@@ -1,13 +1,19 @@
 package pgfilter
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
 	"slices"
 	"strings"
+
+	"gorm.io/gorm/clause"
 )
 
+// ErrInvalidSort is returned when the sort names a column that can't be sorted by.
+var ErrInvalidSort = errors.New("invalid sort column")
+
 func New(sort, order string) *Filter {
 	return &Filter{
 		Search: "",
@@ -23,33 +29,77 @@ type Filter struct {
 	ID     *uint  `query:"id" form:"id" minimum:"1"`
 	Page   int    `query:"page" form:"page" minimum:"1" default:"1"`
 	Limit  int    `query:"limit" form:"limit" minimum:"1" default:"10"`
-	Sort   string `query:"sort" form:"sort" default:"updated_at" example:"updated_at"`
+	Sort   string `query:"sort" form:"sort" default:"updated_at" example:"name,-updated_at"`
 	Order  string `query:"order" form:"order" enums:"asc,desc" default:"desc"`
+
+	// After and Before are cursors from a previous page, read instead of Page. WithCount asks for the total
+	// of rows when reading by cursor, as pages are always counted.
+	After     string `query:"after" form:"after"`
+	Before    string `query:"before" form:"before"`
+	WithCount *bool  `query:"with_count" form:"with_count" example:"true"`
+
+	// Conditions are read from the filter[<field>][<operator>] parameters by BindQuery.
+	Conditions []Condition `query:"-" form:"-" swaggerignore:"true"`
 }
 
-func (s *Filter) ApplySearchLike(columns ...string) (where string) {
-	if len(columns) > 0 && s.Search != "" {
-		whereFunc := func(column, value string) string {
-			return fmt.Sprintf("unaccent(LOWER(%s)) LIKE unaccent(LOWER('%%%s%%'))", column, value)
-		}
+// likeEscaper escapes the LIKE wildcards, and the escape character itself, so they match literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
-		for i, column := range columns {
-			if i > 0 {
-				where += " or "
-			}
-			where += whereFunc(column, s.Search)
-		}
+// ApplySearchLike returns a condition matching rows where any of the columns contains the search,
+// ignoring case and accents, or nil without search or columns. The search is bound as a parameter,
+// while the columns are written as given, so they must never come from the request.
+func (s *Filter) ApplySearchLike(columns ...string) clause.Expression {
+	if len(columns) == 0 || s.Search == "" {
+		return nil
 	}
 
-	return
+	pattern := "%" + likeEscaper.Replace(s.Search) + "%"
+	conditions := make([]string, len(columns))
+	vars := make([]any, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf(`unaccent(LOWER(%s)) LIKE unaccent(LOWER(?)) ESCAPE '\'`, column)
+		vars[i] = pattern
+	}
+
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars}
 }
 
-func (s *Filter) ApplyOrder(tbName *string) string {
+// SortColumns maps the names clients may sort by to the columns they stand for.
+type SortColumns map[string]string
+
+// ApplyOrder returns the ordering requested by Sort, a comma separated list of names from columns, each
+// sorted in Order, or descending when prefixed by "-" and ascending by "+". The tieBreaker column comes
+// last, so rows with equal values keep a stable order across pages. Unknown names return ErrInvalidSort.
+func (s *Filter) ApplyOrder(columns SortColumns, tieBreaker string) (clause.OrderBy, error) {
 	s.check()
-	if tbName != nil && !strings.Contains(s.Sort, ".") {
-		return fmt.Sprintf("%v.%v %v", *tbName, s.Sort, s.Order)
+	orderBy := clause.OrderBy{}
+	seen := map[string]bool{}
+	for _, name := range strings.Split(s.Sort, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.EqualFold(s.Order, "desc")
+		switch {
+		case strings.HasPrefix(name, "-"):
+			name, desc = name[1:], true
+		case strings.HasPrefix(name, "+"):
+			name, desc = name[1:], false
+		}
+
+		column, ok := columns[name]
+		if !ok {
+			return clause.OrderBy{}, ErrInvalidSort
+		}
+
+		if !seen[column] {
+			seen[column] = true
+			orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: desc})
+		}
+	}
+
+	if tieBreaker != "" && !seen[tieBreaker] {
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: clause.Column{Name: tieBreaker, Raw: true}})
 	}
-	return fmt.Sprintf("%v %v", s.Sort, s.Order)
+
+	return orderBy, nil
 }
 
 func (s *Filter) ApplyPagination() (aux bool, offset, limit int) {
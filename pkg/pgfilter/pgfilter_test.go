@@ -1,50 +1,159 @@
 package pgfilter
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func TestApplySearchLike(t *testing.T) {
+	like := `unaccent(LOWER(%s)) LIKE unaccent(LOWER(?)) ESCAPE '\'`
 	tests := []struct {
 		name     string
 		filter   Filter
 		columns  []string
-		expected string
+		expected clause.Expression
 	}{
-		{"no_columns", Filter{Search: "test"}, nil, ""},
-		{"empty_search", Filter{Search: ""}, []string{"name"}, ""},
-		{"single_column", Filter{Search: "test"}, []string{"name"}, "unaccent(LOWER(name)) LIKE unaccent(LOWER('%test%'))"},
-		{"multiple_columns", Filter{Search: "test"}, []string{"name", "description"}, "unaccent(LOWER(name)) LIKE unaccent(LOWER('%test%')) or unaccent(LOWER(description)) LIKE unaccent(LOWER('%test%'))"},
+		{"no_columns", Filter{Search: "test"}, nil, nil},
+		{"empty_search", Filter{Search: ""}, []string{"name"}, nil},
+		{"single_column", Filter{Search: "test"}, []string{"name"}, clause.Expr{
+			SQL:  "(" + fmt.Sprintf(like, "name") + ")",
+			Vars: []any{"%test%"},
+		}},
+		{"multiple_columns", Filter{Search: "test"}, []string{"name", "description"}, clause.Expr{
+			SQL:  "(" + fmt.Sprintf(like, "name") + " OR " + fmt.Sprintf(like, "description") + ")",
+			Vars: []any{"%test%", "%test%"},
+		}},
+		{"quote", Filter{Search: "'"}, []string{"name"}, clause.Expr{
+			SQL:  "(" + fmt.Sprintf(like, "name") + ")",
+			Vars: []any{"%'%"},
+		}},
+		{"wildcards", Filter{Search: `50%_off\`}, []string{"name"}, clause.Expr{
+			SQL:  "(" + fmt.Sprintf(like, "name") + ")",
+			Vars: []any{`%50\%\_off\\%`},
+		}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := tt.filter.ApplySearchLike(tt.columns...)
-			if got != tt.expected {
+			if !reflect.DeepEqual(got, tt.expected) {
 				t.Errorf("expected: %v, got: %v", tt.expected, got)
 			}
 		})
 	}
 }
 
+// FuzzApplySearchLike builds the search query for arbitrary input, checking the SQL never changes and the
+// bound pattern matches the input literally.
+func FuzzApplySearchLike(f *testing.F) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	build := func(search string) *gorm.Statement {
+		filter := Filter{Search: search}
+		return db.Table("usr_user").Where(filter.ApplySearchLike("name", "mail")).Find(&[]map[string]any{}).Statement
+	}
+	expected := build("search").SQL.String()
+
+	for _, seed := range []string{"'", "%", "_", `\`, "' OR 1=1 --", "'); DROP TABLE usr_user; --", "$1", "?", "\x00"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, search string) {
+		if search == "" {
+			return
+		}
+
+		statement := build(search)
+		if got := statement.SQL.String(); got != expected {
+			t.Fatalf("search %q changed the query: %s", search, got)
+		}
+
+		if len(statement.Vars) != 2 {
+			t.Fatalf("search %q bound %d vars", search, len(statement.Vars))
+		}
+
+		for _, v := range statement.Vars {
+			pattern, ok := v.(string)
+			if !ok || !strings.HasPrefix(pattern, "%") || !strings.HasSuffix(pattern, "%") {
+				t.Fatalf("search %q bound %v", search, v)
+			}
+
+			if got := unescapeLike(t, pattern[1:len(pattern)-1]); got != search {
+				t.Fatalf("search %q bound a pattern matching %q", search, got)
+			}
+		}
+	})
+}
+
+// unescapeLike returns the text an escaped LIKE pattern matches, failing on unescaped wildcards.
+func unescapeLike(t *testing.T, pattern string) string {
+	var unescaped strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i++; i == len(pattern) {
+				t.Fatalf("pattern %q ends in an escape", pattern)
+			}
+		case '%', '_':
+			t.Fatalf("pattern %q has an unescaped wildcard", pattern)
+		}
+		unescaped.WriteByte(pattern[i])
+	}
+
+	return unescaped.String()
+}
+
 func TestApplyOrder(t *testing.T) {
+	t.Setenv("API_DEFAULT_SORT", "updated_at")
+	t.Setenv("API_DEFAULT_ORDER", "desc")
+
+	columns := SortColumns{
+		"id":         "users.id",
+		"name":       "users.name",
+		"profile":    "profiles.name",
+		"updated_at": "users.updated_at",
+	}
+	column := func(name string, desc bool) clause.OrderByColumn {
+		return clause.OrderByColumn{Column: clause.Column{Name: name, Raw: true}, Desc: desc}
+	}
+
 	tests := []struct {
 		name     string
 		filter   Filter
-		tbName   *string
-		expected string
+		expected []clause.OrderByColumn
+		err      error
 	}{
-		{"no_table_name", Filter{Sort: "created_at", Order: "asc"}, nil, "created_at asc"},
-		{"table_name_set", Filter{Sort: "created_at", Order: "desc"}, stringPointer("users"), "users.created_at desc"},
-		{"no_sort_column", Filter{Sort: "", Order: "asc"}, nil, " asc"},
+		{"single_column", Filter{Sort: "name", Order: "asc"}, []clause.OrderByColumn{column("users.name", false), column("users.id", false)}, nil},
+		{"alias", Filter{Sort: "profile", Order: "desc"}, []clause.OrderByColumn{column("profiles.name", true), column("users.id", false)}, nil},
+		{"multiple_columns", Filter{Sort: "name,-updated_at", Order: "asc"}, []clause.OrderByColumn{column("users.name", false), column("users.updated_at", true), column("users.id", false)}, nil},
+		{"explicit_ascending", Filter{Sort: "+name, profile", Order: "desc"}, []clause.OrderByColumn{column("users.name", false), column("profiles.name", true), column("users.id", false)}, nil},
+		{"tie_breaker_sorted", Filter{Sort: "-id", Order: "asc"}, []clause.OrderByColumn{column("users.id", true)}, nil},
+		{"repeated_column", Filter{Sort: "name,-name", Order: "asc"}, []clause.OrderByColumn{column("users.name", false), column("users.id", false)}, nil},
+		{"default_sort", Filter{}, []clause.OrderByColumn{column("users.updated_at", true), column("users.id", false)}, nil},
+		{"unknown_column", Filter{Sort: "password", Order: "asc"}, nil, ErrInvalidSort},
+		{"column_name", Filter{Sort: "users.name", Order: "asc"}, nil, ErrInvalidSort},
+		{"empty_item", Filter{Sort: "name,", Order: "asc"}, nil, ErrInvalidSort},
+		{"injection", Filter{Sort: "name; DROP TABLE users", Order: "asc"}, nil, ErrInvalidSort},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.filter.ApplyOrder(tt.tbName)
-			if got != tt.expected {
-				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			got, err := tt.filter.ApplyOrder(columns, "users.id")
+			if err != tt.err {
+				t.Fatalf("expected error: %v, got: %v", tt.err, err)
+			}
+			if !reflect.DeepEqual(got.Columns, tt.expected) {
+				t.Errorf("expected: %v, got: %v", tt.expected, got.Columns)
 			}
 		})
 	}
@@ -145,7 +254,3 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
-
-func stringPointer(s string) *string {
-	return &s
-}
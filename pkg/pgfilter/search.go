@@ -0,0 +1,77 @@
+package pgfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidSearchMode is returned when the search mode isn't one of the modes below.
+var ErrInvalidSearchMode = errors.New("invalid search mode")
+
+// Search modes. Contains matches the search anywhere in the columns with LIKE, full text matches the words of
+// the search as prefixes of the words in a tsvector, and trigram matches columns similar to the search, so
+// misspellings are still found. Full text and trigram results are ranked by relevance.
+const (
+	SearchContains string = "contains"
+	SearchFullText string = "fulltext"
+	SearchTrigram  string = "trigram"
+)
+
+// PrefixQuery returns the tsquery matching every word of the search as a prefix ("jo:* & sil:*"), or an
+// empty string when it has no words. Characters with a meaning in tsquery are dropped, so any search
+// builds a valid query.
+func (s *Filter) PrefixQuery() string {
+	words := strings.FieldsFunc(s.Search, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("@._-", r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if word = strings.Trim(word, "@._-"); word != "" {
+			terms = append(terms, word+":*")
+		}
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// ApplySearchFullText returns the condition matching the search as prefixes in the tsvector column, parsed
+// with the text search config, and the rank of the matching rows, or nil ones without search. Both the
+// column and the config are written as given, so they must never come from the request.
+func (s *Filter) ApplySearchFullText(config, vector string) (where, rank clause.Expression) {
+	query := s.PrefixQuery()
+	if vector == "" || query == "" {
+		return nil, nil
+	}
+
+	tsquery := fmt.Sprintf("to_tsquery('%s', ?)", config)
+	return clause.Expr{SQL: fmt.Sprintf("%s @@ %s", vector, tsquery), Vars: []any{query}},
+		clause.Expr{SQL: fmt.Sprintf("ts_rank(%s, %s)", vector, tsquery), Vars: []any{query}}
+}
+
+// ApplySearchTrigram returns the condition matching rows where the search is similar to a word of any of
+// the columns, ignoring case and accents, and their highest similarity as rank, or nil ones without search
+// or columns. It relies on the pg_trgm extension and the immutable public.f_unaccent function, which the
+// columns' trigram indexes are built on. Columns are written as given, so they must never come from the request.
+func (s *Filter) ApplySearchTrigram(columns ...string) (where, rank clause.Expression) {
+	search := strings.TrimSpace(s.Search)
+	if len(columns) == 0 || search == "" {
+		return nil, nil
+	}
+
+	conditions := make([]string, len(columns))
+	similarities := make([]string, len(columns))
+	vars := make([]any, len(columns))
+	for i, column := range columns {
+		conditions[i] = fmt.Sprintf("public.f_unaccent(LOWER(?)) <%% public.f_unaccent(LOWER(%s))", column)
+		similarities[i] = fmt.Sprintf("word_similarity(public.f_unaccent(LOWER(?)), public.f_unaccent(LOWER(%s)))", column)
+		vars[i] = search
+	}
+
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars},
+		clause.Expr{SQL: "GREATEST(" + strings.Join(similarities, ", ") + ")", Vars: vars}
+}
@@ -0,0 +1,86 @@
+package pgfilter
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		search   string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"single_word", "jo", "jo:*"},
+		{"multiple_words", "  João  da Silva ", "João:* & da:* & Silva:*"},
+		{"mail", "john.doe@mail.com", "john.doe@mail.com:*"},
+		{"operators", "jo & !(sil | x):* <-> 'y'", "jo:* & sil:* & x:* & y:*"},
+		{"only_operators", "& | ! ( ) : * ' \\", ""},
+		{"trimmed_punctuation", "-jo_ ._", "jo:*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{Search: tt.search}
+			if got := filter.PrefixQuery(); got != tt.expected {
+				t.Errorf("expected: %v, got: %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplySearchFullText(t *testing.T) {
+	filter := Filter{Search: "jo sil"}
+	where, rank := filter.ApplySearchFullText("public.simple_unaccent", "users.search_vector")
+
+	expected := clause.Expr{SQL: "users.search_vector @@ to_tsquery('public.simple_unaccent', ?)", Vars: []any{"jo:* & sil:*"}}
+	if !reflect.DeepEqual(where, expected) {
+		t.Errorf("expected: %v, got: %v", expected, where)
+	}
+
+	expected = clause.Expr{SQL: "ts_rank(users.search_vector, to_tsquery('public.simple_unaccent', ?))", Vars: []any{"jo:* & sil:*"}}
+	if !reflect.DeepEqual(rank, expected) {
+		t.Errorf("expected: %v, got: %v", expected, rank)
+	}
+
+	filter = Filter{Search: "&|!"}
+	if where, rank := filter.ApplySearchFullText("public.simple_unaccent", "users.search_vector"); where != nil || rank != nil {
+		t.Errorf("expected no search, got: %v, %v", where, rank)
+	}
+}
+
+func TestApplySearchTrigram(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := Filter{Search: " jhon' "}
+	if where, rank := filter.ApplySearchTrigram(); where != nil || rank != nil {
+		t.Errorf("expected no search without columns, got: %v, %v", where, rank)
+	}
+
+	where, rank := filter.ApplySearchTrigram("users.name", "users.mail")
+	statement := db.Table("users").Where(where).Order(clause.OrderBy{Expression: clause.Expr{SQL: "? DESC", Vars: []any{rank}}}).Find(&[]map[string]any{}).Statement
+
+	expected := `SELECT * FROM "users" WHERE (public.f_unaccent(LOWER($1)) <% public.f_unaccent(LOWER(users.name)) OR public.f_unaccent(LOWER($2)) <% public.f_unaccent(LOWER(users.mail))) ` +
+		`ORDER BY GREATEST(word_similarity(public.f_unaccent(LOWER($3)), public.f_unaccent(LOWER(users.name))), word_similarity(public.f_unaccent(LOWER($4)), public.f_unaccent(LOWER(users.mail)))) DESC`
+	if got := statement.SQL.String(); got != expected {
+		t.Errorf("expected: %v, got: %v", expected, got)
+	}
+
+	vars := []any{"jhon'", "jhon'", "jhon'", "jhon'"}
+	if !reflect.DeepEqual(statement.Vars, vars) {
+		t.Errorf("expected: %v, got: %v", vars, statement.Vars)
+	}
+
+	filter = Filter{Search: "   "}
+	if where, rank := filter.ApplySearchTrigram("users.name"); where != nil || rank != nil {
+		t.Errorf("expected no search, got: %v, %v", where, rank)
+	}
+}
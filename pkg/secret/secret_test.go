@@ -0,0 +1,71 @@
+package secret
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewToken(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"16 bytes", 16},
+		{"32 bytes", 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := NewToken(tt.size)
+			require.NoError(t, err)
+
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			require.NoError(t, err)
+			assert.Len(t, decoded, tt.size)
+
+			other, err := NewToken(tt.size)
+			require.NoError(t, err)
+			assert.NotEqual(t, token, other)
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{"abc", "abc", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Hash(tt.input))
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected bool
+	}{
+		{"equal", "token", "token", true},
+		{"different", "token", "other", false},
+		{"different length", "token", "tokens", false},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Equal(tt.a, tt.b))
+		})
+	}
+}
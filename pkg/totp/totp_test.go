@@ -0,0 +1,106 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 form of the RFC 6238 SHA1 test seed "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTP(t *testing.T) {
+	// RFC 6238 appendix B, SHA1 column.
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	key, err := decodeSecret(rfcSecret)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hotp(key, uint64(Step(time.Unix(tt.unix, 0))), 8))
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	code, err := Code(rfcSecret, time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	_, err = Code("not base32!", time.Now())
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current, err := Code(rfcSecret, now)
+	require.NoError(t, err)
+	previous, err := Code(rfcSecret, now.Add(-Period))
+	require.NoError(t, err)
+	old, err := Code(rfcSecret, now.Add(-3*Period))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		skew     int64
+		step     int64
+		expected bool
+	}{
+		{"current step", rfcSecret, current, 1, Step(now), true},
+		{"previous step within skew", rfcSecret, previous, 1, Step(now) - 1, true},
+		{"previous step without skew", rfcSecret, previous, 0, 0, false},
+		{"outside skew", rfcSecret, old, 1, 0, false},
+		{"wrong length", rfcSecret, current[:5], 1, 0, false},
+		{"invalid secret", "!!", current, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now, tt.skew)
+			assert.Equal(t, tt.expected, ok)
+			assert.Equal(t, tt.step, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	key, err := decodeSecret(secret)
+	require.NoError(t, err)
+	assert.Len(t, key, secretSize)
+
+	code, err := Code(secret, time.Now())
+	require.NoError(t, err)
+	_, ok := Validate(secret, code, time.Now(), 1)
+	assert.True(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Go API", "john.cena", rfcSecret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Go API:john.cena", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Go API", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
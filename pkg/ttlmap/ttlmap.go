@@ -57,13 +57,30 @@ func (s *TTLMap) Get(key string) any {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	if val, ok := s.items[key]; ok {
+	if val, ok := s.items[key]; ok && !val.Expired() {
 		return val.value
 	}
 
 	return nil
 }
 
+// Increment adds one to the counter stored at key and returns it. A missing or expired counter
+// starts over at one, expiring after the given duration.
+func (s *TTLMap) Increment(key string, expiration time.Duration) int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if val, ok := s.items[key]; ok && !val.Expired() {
+		if count, ok := val.value.(int); ok {
+			val.value = count + 1
+			return count + 1
+		}
+	}
+
+	s.items[key] = newItem(1, time.Now().Add(expiration), true)
+	return 1
+}
+
 func (s *TTLMap) Del(key string) {
 	s.m.Lock()
 	defer s.m.Unlock()
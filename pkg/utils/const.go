@@ -1,11 +1,17 @@
 package utils
 
 const (
-	LocalID     string = "localID"
-	LocalUser   string = "localUser"
-	LocalFile   string = "localFile"
-	LocalDTO    string = "localDTO"
-	LocalFilter string = "localFilter"
+	LocalID      string = "localID"
+	LocalUser    string = "localUser"
+	LocalFile    string = "localFile"
+	LocalDTO     string = "localDTO"
+	LocalFilter  string = "localFilter"
+	LocalSession string = "localSession"
+	LocalClaims  string = "localClaims"
+	LocalAPIKey  string = "localAPIKey"
+	LocalActor   string = "localActor"
+
+	LocalCookieAuth string = "localCookieAuth"
 
 	ParamID   string = "id"
 	ParamMail string = "email"
@@ -7,5 +7,31 @@ var (
 	ErrInvalidCredentials  = errors.New("invalid credentials")
 	ErrUserHasPass         = errors.New("user already has password")
 	ErrPasswordsDoNotMatch = errors.New("passwords do not match")
+	ErrPasswordReused      = errors.New("password already used")
 	ErrInvalidID           = errors.New("invalid id")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrInvalidInvitation   = errors.New("invalid invitation")
+	ErrTokenReused         = errors.New("refresh token already used")
+	ErrLoginThrottled      = errors.New("too many failed login attempts")
+	ErrLockedUser          = errors.New("user is locked")
+
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorDisabled    = errors.New("two-factor authentication not enabled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+	ErrInvalidAPIKey        = errors.New("invalid api key")
+	ErrPermissionNotGranted = errors.New("permission not granted")
+	ErrUnknownPermission    = errors.New("unknown permission")
+
+	ErrProfileParentNotFound = errors.New("profile parent not found")
+	ErrProfileCycle          = errors.New("profile can't inherit from itself")
+
+	ErrOIDCDisabled      = errors.New("oidc login disabled")
+	ErrIdentityNotLinked = errors.New("identity not linked to a user")
+	ErrIdentityInUse     = errors.New("identity linked to another user")
+
+	ErrAuthenticatorUnavailable = errors.New("authenticator unavailable")
+	ErrLDAPDisabled             = errors.New("ldap login disabled")
+
+	ErrImpersonationNotAllowed = errors.New("user can't be impersonated")
 )